@@ -41,3 +41,13 @@ func (c *CacheService) Set(key string, value any) bool {
 func (c *CacheService) Delete(key string) {
 	c.cache.Del(key)
 }
+
+// SetWithTTL caches the value until the given ttl runs out instead of the default expiry
+func (c *CacheService) SetWithTTL(key string, value any, ttl time.Duration) bool {
+	return c.cache.SetWithTTL(key, value, 1, ttl)
+}
+
+// Wait blocks until buffered writes are applied so a following Get sees them
+func (c *CacheService) Wait() {
+	c.cache.Wait()
+}
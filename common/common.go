@@ -16,6 +16,13 @@ type ResponsePagination struct {
 	Pages   uint        `json:"pages"`
 }
 
+// FieldError is returned in ResponseHTTP.Data when request fields break a rule
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 // Generic function to filter the map based on a list of allowed keys.
 func FilterMapByKeys(input map[string]any, allowedKeys []string) map[string]any {
 	filtered := make(map[string]any)
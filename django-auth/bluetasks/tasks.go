@@ -1,6 +1,7 @@
 package bluetasks
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strconv"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/bushubdegefu/m-playground/configs"
 	"github.com/bushubdegefu/m-playground/database"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
 	"github.com/bushubdegefu/m-playground/logs"
 
 	"github.com/madflojo/tasks"
@@ -54,5 +56,50 @@ func ScheduledTasks() *tasks.Scheduler {
 		fmt.Println(err)
 	}
 
+	// Reloading the signing key ring so keys rotated by another instance are picked up
+	if _, err := scheduler.Add(&tasks.Task{
+		Interval: services.SigningKeyReloadInterval(),
+		TaskFunc: func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := services.HandlerSigningKeyService.Load(ctx); err != nil {
+				fmt.Printf("error reloading signing keys: %v\n", err)
+			}
+			return nil
+		},
+	}); err != nil {
+		fmt.Println(err)
+	}
+
+	// Deleting sign ups that were never verified so their usernames can be registered again
+	if _, err := scheduler.Add(&tasks.Task{
+		Interval: time.Hour,
+		TaskFunc: func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if _, err := services.HandlerUserService.PurgeUnverifiedUsers(ctx); err != nil {
+				fmt.Printf("error purging unverified users: %v\n", err)
+			}
+			return nil
+		},
+	}); err != nil {
+		fmt.Println(err)
+	}
+
+	// Removing users, groups and permissions kept in the trash longer than TRASH_RETENTION_DAYS
+	if _, err := scheduler.Add(&tasks.Task{
+		Interval: time.Hour,
+		TaskFunc: func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+			if _, err := services.PurgeTrash(ctx); err != nil {
+				fmt.Printf("error purging trash: %v\n", err)
+			}
+			return nil
+		},
+	}); err != nil {
+		fmt.Println(err)
+	}
+
 	return scheduler
 }
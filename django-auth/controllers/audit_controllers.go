@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/labstack/echo/v4"
+)
+
+// GetAuditLogs function to get the audit log with pagination and filters
+// @Summary Get Audit Log
+// @Description Get the recorded changes of users, groups and permissions, newest first
+// @Tags Audit
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param page query int true "page"
+// @Param size query int true "page size"
+// @Param actor_id query string false "ID of the user who made the change"
+// @Param action query string false "create, update, delete, restore, purge, add_relation or remove_relation"
+// @Param entity query string false "user, group, permission or object_permission"
+// @Param entity_id query string false "ID of the changed document"
+// @Param route_name query string false "route the change was made through"
+// @Param trace_id query string false "trace of the request"
+// @Param from query string false "RFC3339 time, changes at or after it"
+// @Param to query string false "RFC3339 time, changes at or before it"
+// @Success 200 {object} common.ResponsePagination{data=[]models.AuditLog}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/audit [get]
+func GetAuditLogs(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	//  parsing Query Prameters
+	Page, _ := strconv.Atoi(contx.QueryParam("page"))
+	Limit, _ := strconv.Atoi(contx.QueryParam("size"))
+	//  checking if query parameters  are correct
+	if Page == 0 || Limit == 0 {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: "Not Allowed, Bad request",
+			Data:    nil,
+		})
+	}
+
+	var from, to time.Time
+	for param, value := range map[string]*time.Time{"from": &from, "to": &to} {
+		raw := contx.QueryParam(param)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+				Success: false,
+				Message: param + " must be an RFC3339 time",
+				Data:    nil,
+			})
+		}
+		*value = parsed
+	}
+
+	filters := map[string]string{}
+	for _, param := range []string{"actor_id", "action", "entity", "entity_id", "route_name", "trace_id"} {
+		filters[param] = contx.QueryParam(param)
+	}
+
+	// Prepare pagination model
+	pagination := models.Pagination{
+		Page: Page - 1, // assuming pages are 0-indexed in backend
+		Size: Limit,
+	}
+
+	entries, totalCount, err := services.HandlerAuditLogService.Get(tracer.Tracer, pagination, filters, from, to)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// Send paginated response
+	return contx.JSON(http.StatusOK, common.ResponsePagination{
+		Success: true,
+		Message: "Success.",
+		Items:   entries,
+		Total:   totalCount,
+		Page:    uint(Page),
+		Size:    uint(Limit),
+	})
+}
+
+// GetAuditLogByID is a function to get an audit log entry by ID
+// @Summary Get Audit Log Entry by ID
+// @Description Get audit log entry by ID
+// @Tags Audit
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param audit_id path string true "Audit Log Entry ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.AuditLog}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/audit/{audit_id} [get]
+func GetAuditLogByID(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	//  parsing Query Prameters
+	id := contx.Param("audit_id")
+
+	entry, err := services.HandlerAuditLogService.GetOne(tracer.Tracer, id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrAuditLogNotFound) {
+			status = http.StatusNotFound
+		}
+		return contx.JSON(status, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Success",
+		Data:    entry,
+	})
+}
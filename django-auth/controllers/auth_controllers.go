@@ -0,0 +1,389 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Login function to authenticate a User and issue tokens
+// @Summary Login
+// @Description Login with username and password to get access and refresh tokens, or an mfa_token when a second factor is needed
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param credentials body models.LoginPost true "Login Credentials"
+// @Param session query bool false "start a cookie session instead of returning tokens"
+// @Success 200 {object} common.ResponseHTTP{data=models.TokenResponse}
+// @Success 202 {object} common.ResponseHTTP{data=models.MFAChallenge}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Failure 403 {object} common.ResponseHTTP{}
+// @Failure 409 {object} common.ResponseHTTP{}
+// @Failure 429 {object} common.ResponseHTTP{}
+// @Router /django_auth/login [post]
+func Login(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	credentials := new(models.LoginPost)
+
+	//first parse request data
+	if err := contx.Bind(&credentials); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(credentials); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// refusing early while the username or the client ip is locked out
+	clientIP := contx.RealIP()
+	if retryAfter, err := services.HandlerLoginAttemptService.Check(tracer.Tracer, credentials.Username, clientIP); err != nil {
+		return loginErrorResponse(contx, retryAfter, err)
+	}
+
+	// authenticate user from service
+	user, err := services.HandlerUserService.Authenticate(tracer.Tracer, credentials.Username, credentials.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) || errors.Is(err, services.ErrInactiveUser) {
+			// a disabled account answers like a wrong password, telling them apart would confirm the password
+			reason := "invalid_credentials"
+			if errors.Is(err, services.ErrInactiveUser) {
+				reason = "inactive_user"
+				log.Printf("login of disabled user %s refused", credentials.Username)
+			}
+			if err := services.HandlerLoginAttemptService.RecordFailure(tracer.Tracer, credentials.Username, clientIP, reason); err != nil {
+				return loginErrorResponse(contx, 0, err)
+			}
+			return contx.JSON(http.StatusUnauthorized, common.ResponseHTTP{
+				Success: false,
+				Message: services.ErrInvalidCredentials.Error(),
+			})
+		}
+		// a directory user whose email is already held by another account
+		var conflict *services.ConflictError
+		if errors.As(err, &conflict) {
+			return conflictErrorResponse(contx, conflict)
+		}
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// a second factor is needed before any token is issued, the failure counters are
+	// only cleared once it passed so a password login can not reset the code guesses
+	if services.MFARequired(user) {
+		stage := utils.MFAStageVerify
+		if !user.MFAEnabled {
+			stage = utils.MFAStageEnroll
+		}
+
+		mfaToken, err := utils.CreateJWTToken(&utils.UserClaim{
+			UserID:   user.ID.Hex(),
+			Username: user.Username,
+			MFAStage: stage,
+		}, utils.MFATokenType)
+		if err != nil {
+			return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
+		return contx.JSON(http.StatusAccepted, common.ResponseHTTP{
+			Success: true,
+			Message: "MFA verification required.",
+			Data: models.MFAChallenge{
+				MFARequired:           user.MFAEnabled,
+				MFAEnrollmentRequired: !user.MFAEnabled,
+				MFAToken:              mfaToken,
+			},
+		})
+	}
+
+	if err := services.HandlerLoginAttemptService.RecordSuccess(tracer.Tracer, credentials.Username); err != nil {
+		return loginErrorResponse(contx, 0, err)
+	}
+
+	claim := utils.UserClaim{
+		UserID:      user.ID.Hex(),
+		Username:    user.Username,
+		Email:       user.Email,
+		IsSuperuser: user.IsSuperuser,
+	}
+
+	// browser clients get a cookie session instead of tokens
+	if wantsSession(contx) {
+		return startSession(contx, claim, nil)
+	}
+
+	tokens, err := issueLoginTokens(tracer.Tracer, claim)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// return tokens if authentication is sucessfull
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Login successful.",
+		Data:    tokens,
+	})
+}
+
+// RefreshToken function to rotate a refresh token into a new token pair
+// @Summary Refresh Token
+// @Description Exchange the refresh token in X-REFRESH-TOKEN for a new token pair, each refresh token works once
+// @Tags Authentication
+// @Security Refresh
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.ResponseHTTP{data=models.TokenResponse}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /django_auth/refresh [post]
+func RefreshToken(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validate refresh token header
+	refreshToken := contx.Request().Header.Get("X-REFRESH-TOKEN")
+	claim, err := utils.ParseJWTToken(refreshToken, utils.RefreshTokenType)
+	if err != nil {
+		return contx.JSON(http.StatusUnauthorized, common.ResponseHTTP{
+			Success: false,
+			Message: "invalid refresh token",
+		})
+	}
+
+	// make sure the user still exists and is allowed to login
+	user, err := services.HandlerUserService.GetOne(tracer.Tracer, claim.UserID)
+	if err != nil || !user.IsActive {
+		return contx.JSON(http.StatusUnauthorized, common.ResponseHTTP{
+			Success: false,
+			Message: "invalid refresh token",
+		})
+	}
+
+	// the new refresh token stays in the family of the one it replaces
+	tokens, refreshRecord, err := newTokenPair(utils.UserClaim{
+		UserID:      user.ID.Hex(),
+		Username:    user.Username,
+		Email:       user.Email,
+		IsSuperuser: user.IsSuperuser,
+		FamilyID:    claim.FamilyID,
+	})
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// refresh tokens are single use, reusing one revokes its whole family
+	if err := services.HandlerRefreshTokenService.Rotate(tracer.Tracer, claim.ID, refreshRecord); err != nil {
+		if errors.Is(err, services.ErrRefreshTokenInvalid) || errors.Is(err, services.ErrRefreshTokenReused) {
+			return contx.JSON(http.StatusUnauthorized, common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// return tokens if refresh is sucessfull
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Token refreshed successfully.",
+		Data:    tokens,
+	})
+}
+
+// Logout function to revoke the current session
+// @Summary Logout
+// @Description Revoke the access token in use and the refresh tokens of its session, or end the cookie session
+// @Tags Authentication
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/logout [post]
+func Logout(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// claim of the caller set by the auth middleware
+	claim := contx.Get("user_claim").(*utils.UserClaim)
+
+	// cookie sessions have no tokens to revoke, the session itself is ended
+	if claim.TokenType == utils.SessionTokenType {
+		return endSession(contx)
+	}
+
+	userID, _ := primitive.ObjectIDFromHex(claim.UserID)
+	if err := services.HandlerRevokedTokenService.Revoke(tracer.Tracer, claim.ID, userID, claim.ExpiresAt.Time); err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := services.HandlerRefreshTokenService.RevokeSession(tracer.Tracer, claim.ID); err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// return success if revoking is sucessfull
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Logged out successfully.",
+		Data:    nil,
+	})
+}
+
+// RevokeUserSessions function to revoke every session of a user
+// @Summary Revoke User Sessions
+// @Description Revoke all access and refresh tokens issued to the user, impersonation tokens for or by the user included, and end their cookie sessions
+// @Tags Authentication
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/user/{user_id}/revoke-sessions [post]
+func RevokeUserSessions(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validate path params
+	user_id := contx.Param("user_id")
+
+	if err := services.HandlerRefreshTokenService.RevokeUserSessions(tracer.Tracer, user_id); err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := services.HandlerSessionService.RevokeUserSessions(tracer.Tracer, user_id); err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// return success if revoking is sucessfull
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "User sessions revoked successfully.",
+		Data:    nil,
+	})
+}
+
+// issues the token pair of a completed login, every login starts a new refresh token family
+func issueLoginTokens(ctx context.Context, claim utils.UserClaim) (*models.TokenResponse, error) {
+	familyID, _ := uuid.NewV7()
+	claim.FamilyID = familyID.String()
+
+	tokens, refreshRecord, err := newTokenPair(claim)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := services.HandlerRefreshTokenService.Create(ctx, refreshRecord); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// signs an access and refresh token pair and returns the refresh token record to persist
+func newTokenPair(claim utils.UserClaim) (*models.TokenResponse, *models.RefreshToken, error) {
+	accessClaim := claim
+	accessClaim.FamilyID = ""
+	accessToken, err := utils.CreateJWTToken(&accessClaim, utils.AccessTokenType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshClaim := claim
+	refreshToken, err := utils.CreateJWTToken(&refreshClaim, utils.RefreshTokenType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userID, err := primitive.ObjectIDFromHex(claim.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens := &models.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(utils.TokenLifetime(utils.AccessTokenType).Seconds()),
+	}
+
+	refreshRecord := &models.RefreshToken{
+		JTI:             refreshClaim.ID,
+		FamilyID:        refreshClaim.FamilyID,
+		UserID:          userID,
+		ExpiresAt:       refreshClaim.ExpiresAt.Time,
+		AccessJTI:       accessClaim.ID,
+		AccessExpiresAt: accessClaim.ExpiresAt.Time,
+	}
+
+	return tokens, refreshRecord, nil
+}
+
+// answers lockouts with 429 and a Retry-After header, anything else is a server error
+func loginErrorResponse(contx echo.Context, retryAfter time.Duration, err error) error {
+	if errors.Is(err, services.ErrLoginLocked) {
+		contx.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		return contx.JSON(http.StatusTooManyRequests, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+		Success: false,
+		Message: err.Error(),
+	})
+}
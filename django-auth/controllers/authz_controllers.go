@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// CheckAuthorization function to check many permissions of a user at once
+// @Summary Check Authorization
+// @Description For downstream services, decide each check for the user or the token holder. Every result says whether it is allowed and why, personal access tokens are limited to their scopes and impersonation tokens never get the superuser bypass
+// @Tags Authentication
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param check body models.AuthzCheckPost true "Authorization checks"
+// @Success 200 {object} common.ResponseHTTP{data=models.AuthzCheckResponse}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/authz/check [post]
+func CheckAuthorization(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	posted_check := new(models.AuthzCheckPost)
+
+	//first parse request data
+	if err := contx.Bind(&posted_check); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(posted_check); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	subject := &services.AuthzSubject{UserID: posted_check.UserID}
+	if posted_check.Token != "" {
+		var err error
+		subject, err = services.AuthzSubjectFromToken(tracer.Tracer, posted_check.Token)
+		if err != nil {
+			return contx.JSON(http.StatusUnauthorized, common.ResponseHTTP{
+				Success: false,
+				Message: "Invalid or expired token.",
+				Data:    nil,
+			})
+		}
+	}
+
+	decisions, err := services.HandlerUserService.CheckAuthorization(tracer.Tracer, *subject, posted_check.Checks)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		return contx.JSON(status, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Success",
+		Data: models.AuthzCheckResponse{
+			UserID:  subject.UserID,
+			Results: decisions,
+		},
+	})
+}
@@ -1,12 +1,14 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/bushubdegefu/m-playground/common"
 	"github.com/bushubdegefu/m-playground/django-auth/models"
 	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
 	"github.com/bushubdegefu/m-playground/observe"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -90,6 +92,7 @@ func GetGroups(contx echo.Context) error {
 // @Produce json
 // @Param group_id path string true "Group ID"
 // @Success 200 {object} common.ResponseHTTP{data=models.GroupGet}
+// @Header 200 {string} ETag "version of the group, send it back in If-Match to patch or delete only that version"
 // @Failure 404 {object} common.ResponseHTTP{}
 // @Router /django_auth/group/{group_id} [get]
 func GetGroupByID(contx echo.Context) error {
@@ -107,6 +110,7 @@ func GetGroupByID(contx echo.Context) error {
 			Message: err.Error(),
 		})
 	}
+	setETag(contx, group.Version)
 
 	// Send paginated response
 	return contx.JSON(http.StatusOK, common.ResponseHTTP{
@@ -127,6 +131,7 @@ func GetGroupByID(contx echo.Context) error {
 // @Param group body models.GroupPost true "Add Group"
 // @Success 200 {object} common.ResponseHTTP{data=models.GroupPost}
 // @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 409 {object} common.ResponseHTTP{data=[]common.FieldError}
 // @Failure 500 {object} common.ResponseHTTP{}
 // @Router /django_auth/group [post]
 func PostGroup(contx echo.Context) error {
@@ -160,6 +165,10 @@ func PostGroup(contx echo.Context) error {
 	// post group from service
 	group, err := services.HandlerGroupService.Create(tracer.Tracer, posted_group)
 	if err != nil {
+		var conflict *services.ConflictError
+		if errors.As(err, &conflict) {
+			return conflictErrorResponse(contx, conflict)
+		}
 		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
@@ -183,8 +192,13 @@ func PostGroup(contx echo.Context) error {
 // @Produce json
 // @Param group body models.GroupPatch true "Patch Group"
 // @Param group_id path string true "Group ID"
-// @Success 200 {object} common.ResponseHTTP{data=models.GroupPatch}
+// @Param If-Match header string false "ETag of the group as read, 412 when it changed since"
+// @Success 200 {object} common.ResponseHTTP{data=models.GroupGet}
+// @Header 200 {string} ETag "version of the group after the update"
 // @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 409 {object} common.ResponseHTTP{data=[]common.FieldError}
+// @Failure 412 {object} common.ResponseHTTP{}
 // @Failure 500 {object} common.ResponseHTTP{}
 // @Router /django_auth/group/{group_id} [patch]
 func PatchGroup(contx echo.Context) error {
@@ -217,16 +231,34 @@ func PatchGroup(contx echo.Context) error {
 		})
 	}
 
+	// the update only goes through while the group is still the version the client read
+	expectedVersion, ok := ifMatchVersion(contx)
+	if !ok {
+		return preconditionFailedResponse(contx)
+	}
+
 	// patch group from service
-	group, err := services.HandlerGroupService.Update(tracer.Tracer, patch_group, id)
+	group, err := services.HandlerGroupService.Update(tracer.Tracer, patch_group, id, expectedVersion)
 	if err != nil {
-		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+		var conflict *services.ConflictError
+		if errors.As(err, &conflict) {
+			return conflictErrorResponse(contx, conflict)
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrGroupNotFound) {
+			status = http.StatusNotFound
+		}
+		if errors.Is(err, services.ErrVersionMismatch) {
+			status = http.StatusPreconditionFailed
+		}
+		return contx.JSON(status, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
 		})
 	}
 
 	// return data if transaction is sucessfull
+	setETag(contx, group.Version)
 	return contx.JSON(http.StatusOK, common.ResponseHTTP{
 		Success: true,
 		Message: "Group updated successfully.",
@@ -236,14 +268,16 @@ func PatchGroup(contx echo.Context) error {
 
 // DeleteGroups function removes a group by ID
 // @Summary Remove Group by ID
-// @Description Remove group by ID
+// @Description Move the group to the trash, where it can be restored until TRASH_RETENTION_DAYS passed. Removed for good at once when SOFT_DELETE_ENABLED is false
 // @Tags Groups
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param group_id path string true "Group ID"
+// @Param If-Match header string false "ETag of the group as read, 412 when it changed since"
 // @Success 200 {object} common.ResponseHTTP{}
 // @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 412 {object} common.ResponseHTTP{}
 // @Failure 503 {object} common.ResponseHTTP{}
 // @Router /django_auth/group/{group_id} [delete]
 func DeleteGroup(contx echo.Context) error {
@@ -254,9 +288,24 @@ func DeleteGroup(contx echo.Context) error {
 	id := contx.Param("group_id")
 
 	// delete group from service
-	err := services.HandlerGroupService.Delete(tracer.Tracer, id)
+	// claim of the caller set by the auth middleware
+	claim := contx.Get("user_claim").(*utils.UserClaim)
+
+	expectedVersion, ok := ifMatchVersion(contx)
+	if !ok {
+		return preconditionFailedResponse(contx)
+	}
+
+	err := services.HandlerGroupService.Delete(tracer.Tracer, id, claim.UserID, expectedVersion)
 	if err != nil {
-		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrGroupNotFound) {
+			status = http.StatusNotFound
+		}
+		if errors.Is(err, services.ErrVersionMismatch) {
+			status = http.StatusPreconditionFailed
+		}
+		return contx.JSON(status, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
 		})
@@ -283,6 +332,7 @@ func DeleteGroup(contx echo.Context) error {
 // @Param permission_id path string true "Permission ID"
 // @Param group_id path string true "Group ID"
 // @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
 // @Router /django_auth/grouppermission/{permission_id}/{group_id} [post]
 func AddPermissionToGroup(contx echo.Context) error {
 	//  Geting tracer
@@ -296,7 +346,11 @@ func AddPermissionToGroup(contx echo.Context) error {
 
 	err := services.HandlerGroupService.AddGroupToPermission(tracer.Tracer, group_id, permission_id)
 	if err != nil {
-		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrGroupNotFound) || errors.Is(err, services.ErrPermissionNotFound) {
+			status = http.StatusNotFound
+		}
+		return contx.JSON(status, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
 		})
@@ -321,6 +375,7 @@ func AddPermissionToGroup(contx echo.Context) error {
 // @Param group_id path string true "Group ID"
 // @Success 200 {object} common.ResponseHTTP{data=models.GroupPost}
 // @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
 // @Failure 500 {object} common.ResponseHTTP{}
 // @Router /django_auth/grouppermission/{permission_id}/{group_id} [delete]
 func DeletePermissionFromGroup(contx echo.Context) error {
@@ -336,7 +391,11 @@ func DeletePermissionFromGroup(contx echo.Context) error {
 	// removing PermissionFromGroup
 	err := services.HandlerGroupService.RemoveGroupFromPermission(tracer.Tracer, group_id, permission_id)
 	if err != nil {
-		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrGroupNotFound) || errors.Is(err, services.ErrPermissionNotFound) {
+			status = http.StatusNotFound
+		}
+		return contx.JSON(status, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
 		})
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// ##########################################################
+// ##########  Responses shared by the controllers
+// ##########################################################
+
+// answers password policy violations with 400 and a field error per violated rule
+func passwordPolicyErrorResponse(contx echo.Context, field string, policyErr *utils.PasswordPolicyError) error {
+	return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+		Success: false,
+		Message: "password does not meet the password policy",
+		Data:    policyErr.FieldErrors(field),
+	})
+}
+
+// conflictErrorResponse answers 409 naming the field whose value is already taken
+func conflictErrorResponse(contx echo.Context, conflict *services.ConflictError) error {
+	return contx.JSON(http.StatusConflict, common.ResponseHTTP{
+		Success: false,
+		Message: conflict.Error(),
+		Data:    []common.FieldError{{Field: conflict.Field, Code: conflict.Code(), Message: conflict.Error()}},
+	})
+}
+
+// setETag sends the version of the user, group or permission as a strong ETag
+func setETag(contx echo.Context, version int64) {
+	contx.Response().Header().Set("ETag", `"`+strconv.FormatInt(version, 10)+`"`)
+}
+
+// ifMatchVersion reads the version a PATCH or DELETE expects from If-Match, nil when the header is missing or *.
+// ok is false for a value that can never match one of the ETags, weak ones included as If-Match compares strongly
+func ifMatchVersion(contx echo.Context) (version *int64, ok bool) {
+	header := strings.TrimSpace(contx.Request().Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+	if len(header) < 2 || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		return nil, false
+	}
+	parsed, err := strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	return &parsed, true
+}
+
+// preconditionFailedResponse answers 412 when If-Match names a version that is no longer current
+func preconditionFailedResponse(contx echo.Context) error {
+	return contx.JSON(http.StatusPreconditionFailed, common.ResponseHTTP{
+		Success: false,
+		Message: services.ErrVersionMismatch.Error(),
+	})
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ImpersonateUser function to login as another user
+// @Summary Impersonate User
+// @Description Superuser only, issue a short lived access token acting as the user. The token names the superuser in its act claim, has no superuser powers and can not impersonate again, logout ends the impersonation
+// @Tags Authentication
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.ImpersonationResponse}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 403 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/user/{user_id}/impersonate [post]
+func ImpersonateUser(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// claim of the caller set by the auth middleware
+	claim := contx.Get("user_claim").(*utils.UserClaim)
+
+	// validate path params
+	user_id := contx.Param("user_id")
+
+	impersonationClaim, err := services.HandlerUserService.ImpersonationClaim(tracer.Tracer, claim, user_id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrImpersonationForbidden), errors.Is(err, services.ErrImpersonationChained):
+			status = http.StatusForbidden
+		case errors.Is(err, services.ErrImpersonationTarget):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrUserNotFound):
+			status = http.StatusNotFound
+		}
+		return contx.JSON(status, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// only an access token, a refresh token would let the impersonation outlive its short lifetime
+	accessToken, err := utils.CreateJWTToken(impersonationClaim, utils.AccessTokenType)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// tracked like the tokens of a login so revoking the sessions of the user or of the superuser
+	// denylists it, the record is revoked from the start as there is no refresh token to rotate
+	userID, _ := primitive.ObjectIDFromHex(impersonationClaim.UserID)
+	impersonatorID, _ := primitive.ObjectIDFromHex(impersonationClaim.Act.UserID)
+	tokenRecord := &models.RefreshToken{
+		JTI:             impersonationClaim.ID,
+		FamilyID:        impersonationClaim.ID,
+		UserID:          userID,
+		ImpersonatorID:  impersonatorID,
+		ExpiresAt:       impersonationClaim.ExpiresAt.Time,
+		RevokedAt:       time.Now(),
+		AccessJTI:       impersonationClaim.ID,
+		AccessExpiresAt: impersonationClaim.ExpiresAt.Time,
+	}
+	if err := services.HandlerRefreshTokenService.Create(tracer.Tracer, tokenRecord); err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Impersonation started.",
+		Data: models.ImpersonationResponse{
+			AccessToken:    accessToken,
+			TokenType:      "Bearer",
+			ExpiresIn:      int64(impersonationClaim.ExpiresAt.Sub(impersonationClaim.IssuedAt.Time).Seconds()),
+			UserID:         impersonationClaim.UserID,
+			Username:       impersonationClaim.Username,
+			ImpersonatorID: impersonationClaim.Act.UserID,
+		},
+	})
+}
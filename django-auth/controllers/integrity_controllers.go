@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/labstack/echo/v4"
+)
+
+// GetOrphans function to report dangling references
+// @Summary Get Orphans
+// @Description Report group, permission and object permission references to users, groups or permissions that no longer exist
+// @Tags Integrity
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.ResponseHTTP{data=models.OrphanReport}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/integrity/orphans [get]
+func GetOrphans(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	report, err := services.FindOrphans(tracer.Tracer, false)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Success",
+		Data:    report,
+	})
+}
+
+// RepairOrphans function to remove dangling references
+// @Summary Repair Orphans
+// @Description Pull ids of missing groups and permissions from users and groups and delete object permissions naming a missing document, returns what was removed
+// @Tags Integrity
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.ResponseHTTP{data=models.OrphanReport}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/integrity/orphans/repair [post]
+func RepairOrphans(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	report, err := services.FindOrphans(tracer.Tracer, true)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Orphans repaired successfully.",
+		Data:    report,
+	})
+}
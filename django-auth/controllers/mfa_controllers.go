@@ -0,0 +1,436 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// maps MFA service errors to a response status
+func mfaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrMFAInvalidCode):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrMFANotEnrolling),
+		errors.Is(err, services.ErrMFAAlreadyEnabled),
+		errors.Is(err, services.ErrMFANotEnabled),
+		errors.Is(err, services.ErrMFAUserID):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrUserNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parses the mfa_token handed out by login and checks it is for the expected step
+func parseMFAToken(token string, stage string) (*utils.UserClaim, error) {
+	claim, err := utils.ParseJWTToken(token, utils.MFATokenType)
+	if err != nil {
+		return nil, err
+	}
+	if claim.MFAStage != stage {
+		return nil, errors.New("mfa token is not valid for this step")
+	}
+	return claim, nil
+}
+
+// LoginMFA function to finish a login with a TOTP or recovery code
+// @Summary Login MFA Verification
+// @Description Exchange the mfa_token from login and a TOTP or recovery code for tokens
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param verification body models.MFALoginPost true "MFA Verification"
+// @Param session query bool false "start a cookie session instead of returning tokens"
+// @Success 200 {object} common.ResponseHTTP{data=models.TokenResponse}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Failure 403 {object} common.ResponseHTTP{}
+// @Failure 429 {object} common.ResponseHTTP{}
+// @Router /django_auth/login/mfa [post]
+func LoginMFA(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	verification := new(models.MFALoginPost)
+
+	//first parse request data
+	if err := contx.Bind(&verification); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(verification); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	claim, err := parseMFAToken(verification.MFAToken, utils.MFAStageVerify)
+	if err != nil {
+		return contx.JSON(http.StatusUnauthorized, common.ResponseHTTP{
+			Success: false,
+			Message: "invalid mfa token",
+		})
+	}
+
+	// codes are short, guessing them counts against the same lockout as passwords
+	clientIP := contx.RealIP()
+	if retryAfter, err := services.HandlerLoginAttemptService.Check(tracer.Tracer, claim.Username, clientIP); err != nil {
+		return loginErrorResponse(contx, retryAfter, err)
+	}
+
+	if err := services.HandlerUserService.VerifyMFA(tracer.Tracer, claim.UserID, verification.Code); err != nil {
+		if errors.Is(err, services.ErrMFAInvalidCode) {
+			if err := services.HandlerLoginAttemptService.RecordFailure(tracer.Tracer, claim.Username, clientIP, "invalid_mfa_code"); err != nil {
+				return loginErrorResponse(contx, 0, err)
+			}
+		}
+		return contx.JSON(mfaErrorStatus(err), common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return completeMFALogin(contx, claim.UserID, nil)
+}
+
+// LoginMFAEnroll function to start the enrollment an MFA enforced login requires
+// @Summary Login MFA Enrollment
+// @Description Get a TOTP secret using the mfa_token from login when MFA enrollment is required
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param enrollment body models.MFAEnrollPost true "MFA Enrollment"
+// @Success 200 {object} common.ResponseHTTP{data=models.MFAEnrollment}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /django_auth/login/mfa/enroll [post]
+func LoginMFAEnroll(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	enrollment := new(models.MFAEnrollPost)
+
+	//first parse request data
+	if err := contx.Bind(&enrollment); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(enrollment); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	claim, err := parseMFAToken(enrollment.MFAToken, utils.MFAStageEnroll)
+	if err != nil {
+		return contx.JSON(http.StatusUnauthorized, common.ResponseHTTP{
+			Success: false,
+			Message: "invalid mfa token",
+		})
+	}
+
+	secret, err := services.HandlerUserService.BeginMFAEnrollment(tracer.Tracer, claim.UserID)
+	if err != nil {
+		return contx.JSON(mfaErrorStatus(err), common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Add the secret to your authenticator app and confirm with a code.",
+		Data:    secret,
+	})
+}
+
+// LoginMFAConfirm function to confirm enrollment and finish an MFA enforced login
+// @Summary Login MFA Enrollment Confirmation
+// @Description Confirm the TOTP secret with a code, returns recovery codes and tokens
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param verification body models.MFALoginPost true "MFA Confirmation"
+// @Param session query bool false "start a cookie session instead of returning tokens"
+// @Success 200 {object} common.ResponseHTTP{data=models.MFARecoveryCodes}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Failure 403 {object} common.ResponseHTTP{}
+// @Router /django_auth/login/mfa/confirm [post]
+func LoginMFAConfirm(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	verification := new(models.MFALoginPost)
+
+	//first parse request data
+	if err := contx.Bind(&verification); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(verification); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	claim, err := parseMFAToken(verification.MFAToken, utils.MFAStageEnroll)
+	if err != nil {
+		return contx.JSON(http.StatusUnauthorized, common.ResponseHTTP{
+			Success: false,
+			Message: "invalid mfa token",
+		})
+	}
+
+	recoveryCodes, err := services.HandlerUserService.ConfirmMFAEnrollment(tracer.Tracer, claim.UserID, verification.Code)
+	if err != nil {
+		return contx.JSON(mfaErrorStatus(err), common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return completeMFALogin(contx, claim.UserID, recoveryCodes)
+}
+
+// issues the login tokens once the second factor passed
+func completeMFALogin(contx echo.Context, userID string, recoveryCodes []string) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// make sure the user is still allowed to login
+	user, err := services.HandlerUserService.GetOne(tracer.Tracer, userID)
+	if err != nil || !user.IsActive {
+		return contx.JSON(http.StatusUnauthorized, common.ResponseHTTP{
+			Success: false,
+			Message: services.ErrInactiveUser.Error(),
+		})
+	}
+
+	if err := services.HandlerLoginAttemptService.RecordSuccess(tracer.Tracer, user.Username); err != nil {
+		return loginErrorResponse(contx, 0, err)
+	}
+
+	claim := utils.UserClaim{
+		UserID:      user.ID.Hex(),
+		Username:    user.Username,
+		Email:       user.Email,
+		IsSuperuser: user.IsSuperuser,
+	}
+
+	// browser clients get a cookie session instead of tokens
+	if wantsSession(contx) {
+		return startSession(contx, claim, recoveryCodes)
+	}
+
+	tokens, err := issueLoginTokens(tracer.Tracer, claim)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if recoveryCodes != nil {
+		return contx.JSON(http.StatusOK, common.ResponseHTTP{
+			Success: true,
+			Message: "MFA enabled, store the recovery codes somewhere safe as they will not be shown again.",
+			Data: models.MFARecoveryCodes{
+				RecoveryCodes: recoveryCodes,
+				Tokens:        tokens,
+			},
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Login successful.",
+		Data:    tokens,
+	})
+}
+
+// EnrollMyMFA function to start TOTP enrollment for the current user
+// @Summary Start MFA Enrollment
+// @Description Get a TOTP secret and otpauth uri for your account
+// @Tags MFA
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.ResponseHTTP{data=models.MFAEnrollment}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 403 {object} common.ResponseHTTP{}
+// @Router /django_auth/me/mfa/enroll [post]
+func EnrollMyMFA(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// claim of the caller set by the auth middleware
+	claim := contx.Get("user_claim").(*utils.UserClaim)
+
+	// the second factor belongs to the account owner, an impersonator must not set it up
+	if claim.IsImpersonated() {
+		return contx.JSON(http.StatusForbidden, common.ResponseHTTP{
+			Success: false,
+			Message: "MFA can not be enrolled while impersonating",
+		})
+	}
+
+	secret, err := services.HandlerUserService.BeginMFAEnrollment(tracer.Tracer, claim.UserID)
+	if err != nil {
+		return contx.JSON(mfaErrorStatus(err), common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Add the secret to your authenticator app and confirm with a code.",
+		Data:    secret,
+	})
+}
+
+// ConfirmMyMFA function to confirm TOTP enrollment for the current user
+// @Summary Confirm MFA Enrollment
+// @Description Enable MFA by confirming a code from the authenticator app, returns recovery codes
+// @Tags MFA
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param verification body models.MFACodePost true "MFA Confirmation"
+// @Success 200 {object} common.ResponseHTTP{data=models.MFARecoveryCodes}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Failure 403 {object} common.ResponseHTTP{}
+// @Router /django_auth/me/mfa/confirm [post]
+func ConfirmMyMFA(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// claim of the caller set by the auth middleware
+	claim := contx.Get("user_claim").(*utils.UserClaim)
+
+	// confirming would hand the recovery codes of the account to the impersonator
+	if claim.IsImpersonated() {
+		return contx.JSON(http.StatusForbidden, common.ResponseHTTP{
+			Success: false,
+			Message: "MFA can not be enrolled while impersonating",
+		})
+	}
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	verification := new(models.MFACodePost)
+
+	//first parse request data
+	if err := contx.Bind(&verification); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(verification); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	recoveryCodes, err := services.HandlerUserService.ConfirmMFAEnrollment(tracer.Tracer, claim.UserID, verification.Code)
+	if err != nil {
+		return contx.JSON(mfaErrorStatus(err), common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "MFA enabled, store the recovery codes somewhere safe as they will not be shown again.",
+		Data: models.MFARecoveryCodes{
+			RecoveryCodes: recoveryCodes,
+		},
+	})
+}
+
+// ResetUserMFA function to turn MFA off for a locked out user
+// @Summary Reset User MFA
+// @Description Remove a user's TOTP secret and recovery codes
+// @Tags MFA
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/user/{user_id}/mfa [delete]
+func ResetUserMFA(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validate path params
+	user_id := contx.Param("user_id")
+
+	if err := services.HandlerUserService.ResetMFA(tracer.Tracer, user_id); err != nil {
+		return contx.JSON(mfaErrorStatus(err), common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "User MFA reset successfully.",
+		Data:    nil,
+	})
+}
@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// GetObjectPermissions function to get Object Permissions with pagination and filters
+// @Summary Get Object Permissions
+// @Description Get permissions granted on single documents, filtered by exact values
+// @Tags ObjectPermissions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int true "page"
+// @Param size query int true "page size"
+// @Param permission_id query string false "Filter by permission_id optional field string"
+// @Param user_id query string false "Filter by user_id optional field string"
+// @Param group_id query string false "Filter by group_id optional field string"
+// @Param object_type query string false "Filter by object_type optional field string"
+// @Param object_id query string false "Filter by object_id optional field string"
+// @Success 200 {object} common.ResponsePagination{data=[]models.ObjectPermissionGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /django_auth/objectpermission [get]
+func GetObjectPermissions(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	//  parsing Query Prameters
+	Page, _ := strconv.Atoi(contx.QueryParam("page"))
+	Limit, _ := strconv.Atoi(contx.QueryParam("size"))
+	//  checking if query parameters  are correct
+	if Page == 0 || Limit == 0 {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: "Not Allowed, Bad request",
+			Data:    nil,
+		})
+	}
+
+	// Getting filter fields
+	filters := make(map[string]string)
+	for _, field := range []string{"permission_id", "user_id", "group_id", "object_type", "object_id"} {
+		filters[field] = contx.QueryParam(field)
+	}
+
+	// Prepare pagination model
+	pagination := models.Pagination{
+		Page: Page - 1, // assuming pages are 0-indexed in backend
+		Size: Limit,
+	}
+
+	// Fetch grants from service
+	grants, totalCount, err := services.HandlerObjectPermissionService.Get(tracer.Tracer, pagination, filters)
+	if err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// Send paginated response
+	return contx.JSON(http.StatusOK, common.ResponsePagination{
+		Success: true,
+		Message: "Success.",
+		Items:   grants,
+		Total:   totalCount,
+		Page:    uint(Page),
+		Size:    uint(Limit),
+	})
+}
+
+// GetObjectPermissionByID is a function to get an Object Permission by ID
+// @Summary Get Object Permission by ID
+// @Description Get object permission by ID
+// @Tags ObjectPermissions
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param objectpermission_id path string true "Object Permission ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.ObjectPermissionGet}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /django_auth/objectpermission/{objectpermission_id} [get]
+func GetObjectPermissionByID(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	//  parsing Query Prameters
+	id := contx.Param("objectpermission_id")
+
+	// Fetch grant from service
+	grant, err := services.HandlerObjectPermissionService.GetOne(tracer.Tracer, id)
+	if err != nil {
+		return contx.JSON(http.StatusNotFound, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Success",
+		Data:    grant,
+	})
+}
+
+// Add Object Permission to data
+// @Summary Add a new Object Permission
+// @Description Grant a user or a group a permission on a single user, group or permission document
+// @Tags ObjectPermissions
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param grant body models.ObjectPermissionPost true "Add Object Permission"
+// @Success 200 {object} common.ResponseHTTP{data=models.ObjectPermissionGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 409 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/objectpermission [post]
+func PostObjectPermission(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	posted_grant := new(models.ObjectPermissionPost)
+
+	//first parse request data
+	if err := contx.Bind(&posted_grant); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(posted_grant); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// post grant from service
+	grant, err := services.HandlerObjectPermissionService.Create(tracer.Tracer, posted_grant)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrObjectPermissionTarget):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrObjectPermissionExists):
+			status = http.StatusConflict
+		}
+		return contx.JSON(status, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// return data if transaction is sucessfull
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Object permission granted successfully.",
+		Data:    grant,
+	})
+}
+
+// DeleteObjectPermission function removes an Object Permission by ID
+// @Summary Remove Object Permission by ID
+// @Description Revoke a permission granted on a single document
+// @Tags ObjectPermissions
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param objectpermission_id path string true "Object Permission ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 503 {object} common.ResponseHTTP{}
+// @Router /django_auth/objectpermission/{objectpermission_id} [delete]
+func DeleteObjectPermission(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validate path params
+	id := contx.Param("objectpermission_id")
+
+	// delete grant from service
+	err := services.HandlerObjectPermissionService.Delete(tracer.Tracer, id)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// Return success respons
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Object permission revoked successfully.",
+		Data:    nil,
+	})
+}
@@ -0,0 +1,268 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// GetOIDCClients function to get OIDC Clients with pagination and searchFields
+// @Summary Get OIDC Clients
+// @Description Get OIDC Clients
+// @Tags OIDCClients
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int true "page"
+// @Param size query int true "page size"
+// @Param name query string false "Search by name optional field string"
+// @Param client_id query string false "Search by client_id optional field string"
+// @Success 200 {object} common.ResponsePagination{data=[]models.OIDCClientGet}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /django_auth/oidcclient [get]
+func GetOIDCClients(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	//  parsing Query Prameters
+	Page, _ := strconv.Atoi(contx.QueryParam("page"))
+	Limit, _ := strconv.Atoi(contx.QueryParam("size"))
+	//  checking if query parameters  are correct
+	if Page == 0 || Limit == 0 {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: "Not Allowed, Bad request",
+			Data:    nil,
+		})
+	}
+
+	// Getting search fields
+	searchTerm := make(map[string]any)
+	if err := contx.Bind(&searchTerm); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	searchFields := []string{"name", "client_id"}
+	filteredSearchTerm := common.FilterSearchTerms(searchTerm, searchFields)
+
+	// Prepare pagination model
+	pagination := models.Pagination{
+		Page: Page - 1, // assuming pages are 0-indexed in backend
+		Size: Limit,
+	}
+
+	// Fetch clients from service
+	clients, totalCount, err := services.HandlerOIDCClientService.Get(tracer.Tracer, pagination, searchFields, filteredSearchTerm)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// Send paginated response
+	return contx.JSON(http.StatusOK, common.ResponsePagination{
+		Success: true,
+		Message: "Success.",
+		Items:   clients,
+		Total:   totalCount,
+		Page:    uint(Page),
+		Size:    uint(Limit),
+	})
+}
+
+// GetOIDCClientByID is a function to get an OIDC Client by ID
+// @Summary Get OIDC Client by ID
+// @Description Get OIDC client by ID
+// @Tags OIDCClients
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param oidcclient_id path string true "OIDC Client ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.OIDCClientGet}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /django_auth/oidcclient/{oidcclient_id} [get]
+func GetOIDCClientByID(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	//  parsing Query Prameters
+	id := contx.Param("oidcclient_id")
+
+	// Fetch client from service
+	client, err := services.HandlerOIDCClientService.GetOne(tracer.Tracer, id)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Success",
+		Data:    client,
+	})
+}
+
+// Add OIDC Client to data
+// @Summary Add a new OIDC Client
+// @Description Register a relying party, the client_secret of confidential clients is only shown once
+// @Tags OIDCClients
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param client body models.OIDCClientPost true "Add OIDC Client"
+// @Success 200 {object} common.ResponseHTTP{data=models.OIDCClientCreated}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/oidcclient [post]
+func PostOIDCClient(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	posted_client := new(models.OIDCClientPost)
+
+	//first parse request data
+	if err := contx.Bind(&posted_client); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(posted_client); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// post client from service
+	client, err := services.HandlerOIDCClientService.Create(tracer.Tracer, posted_client)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// return data if transaction is sucessfull
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "OIDC client created successfully.",
+		Data:    client,
+	})
+}
+
+// Patch OIDC Client to data
+// @Summary Patch OIDC Client
+// @Description Patch OIDC Client
+// @Tags OIDCClients
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param client body models.OIDCClientPatch true "Patch OIDC Client"
+// @Param oidcclient_id path string true "OIDC Client ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.OIDCClientGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/oidcclient/{oidcclient_id} [patch]
+func PatchOIDCClient(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validator initialization
+	validate := validator.New()
+
+	// validate path params
+	id := contx.Param("oidcclient_id")
+
+	// validate data struct
+	patch_client := new(models.OIDCClientPatch)
+	if err := contx.Bind(&patch_client); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(patch_client); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// patch client from service
+	client, err := services.HandlerOIDCClientService.Update(tracer.Tracer, patch_client, id)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// return data if transaction is sucessfull
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "OIDC client updated successfully.",
+		Data:    client,
+	})
+}
+
+// DeleteOIDCClient function removes an OIDC Client by ID
+// @Summary Remove OIDC Client by ID
+// @Description Remove OIDC client by ID
+// @Tags OIDCClients
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param oidcclient_id path string true "OIDC Client ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 503 {object} common.ResponseHTTP{}
+// @Router /django_auth/oidcclient/{oidcclient_id} [delete]
+func DeleteOIDCClient(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validate path params
+	id := contx.Param("oidcclient_id")
+
+	// delete client from service
+	err := services.HandlerOIDCClientService.Delete(tracer.Tracer, id)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// Return success respons
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "OIDC client deleted successfully.",
+		Data:    nil,
+	})
+}
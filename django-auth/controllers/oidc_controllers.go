@@ -0,0 +1,348 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OpenIDConfiguration function to serve the OpenID Provider metadata
+// @Summary OpenID Connect Discovery
+// @Description OpenID Provider metadata relying parties configure themselves from
+// @Tags OIDC
+// @Produce json
+// @Success 200 {object} models.OIDCDiscovery
+// @Router /django_auth/.well-known/openid-configuration [get]
+func OpenIDConfiguration(contx echo.Context) error {
+	issuer := utils.OIDCIssuer()
+
+	return contx.JSON(http.StatusOK, models.OIDCDiscovery{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/oidc/authorize",
+		TokenEndpoint:                     issuer + "/oidc/token",
+		UserinfoEndpoint:                  issuer + "/oidc/userinfo",
+		JwksURI:                           issuer + "/oidc/jwks",
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  utils.SupportedKeyAlgorithms,
+		ScopesSupported:                   services.OIDCScopes,
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "preferred_username", "name", "given_name", "family_name", "email", "groups"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+	})
+}
+
+// JSONWebKeySet function to serve the keys id tokens are signed with
+// @Summary JSON Web Key Set
+// @Description Public keys to verify id tokens with
+// @Tags OIDC
+// @Produce json
+// @Success 200 {object} utils.JSONWebKeySet
+// @Failure 500 {object} models.OIDCError
+// @Router /django_auth/oidc/jwks [get]
+func JSONWebKeySet(contx echo.Context) error {
+	keySet, err := utils.KeyRingJWKS()
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, models.OIDCError{Error: "server_error", ErrorDescription: err.Error()})
+	}
+	return contx.JSON(http.StatusOK, keySet)
+}
+
+// the signed in user of the request, the authorize endpoint is public so the token is checked here
+func currentUserClaim(contx echo.Context) *utils.UserClaim {
+	// browsers sent here by a relying party carry the session cookie instead of a token
+	if cookie, err := contx.Cookie(services.SessionCookieName()); err == nil {
+		session, err := services.HandlerSessionService.Authenticate(contx.Request().Context(), cookie.Value)
+		if err != nil {
+			return nil
+		}
+		claim, err := services.HandlerSessionService.UserClaim(contx.Request().Context(), session)
+		if err != nil {
+			return nil
+		}
+		return claim
+	}
+
+	claim, err := utils.ParseJWTToken(contx.Request().Header.Get("x-app-token"), utils.AccessTokenType)
+	if err != nil {
+		return nil
+	}
+
+	// relying parties must not get codes for the user from someone impersonating them
+	if claim.IsImpersonated() {
+		return nil
+	}
+
+	revoked, err := services.HandlerRevokedTokenService.IsRevoked(contx.Request().Context(), claim.ID)
+	if err != nil || revoked {
+		return nil
+	}
+	return claim
+}
+
+// appends the parameters to the client's redirect uri
+func oidcRedirectURL(redirectURI string, params url.Values) string {
+	separator := "?"
+	if strings.Contains(redirectURI, "?") {
+		separator = "&"
+	}
+	return redirectURI + separator + params.Encode()
+}
+
+// authorize validates the authorization request and issues a code for the signed in user,
+// it returns the url to send the browser to or an error body when redirecting is not safe
+func authorize(contx echo.Context) (string, int, *models.OIDCError) {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	request := new(models.OIDCAuthorizeRequest)
+	if err := contx.Bind(request); err != nil {
+		return "", http.StatusBadRequest, &models.OIDCError{Error: "invalid_request", ErrorDescription: err.Error()}
+	}
+
+	// the client and redirect uri are checked first, errors before that must not redirect
+	client, err := services.HandlerOIDCClientService.FindByClientID(tracer.Tracer, request.ClientID)
+	if err != nil {
+		return "", http.StatusBadRequest, &models.OIDCError{Error: "invalid_request", ErrorDescription: "unknown client_id"}
+	}
+	if !services.AllowsRedirectURI(client, request.RedirectURI) {
+		return "", http.StatusBadRequest, &models.OIDCError{Error: "invalid_request", ErrorDescription: "redirect_uri is not registered for the client"}
+	}
+
+	redirectError := func(code, description string) (string, int, *models.OIDCError) {
+		params := url.Values{"error": {code}, "error_description": {description}}
+		if request.State != "" {
+			params.Set("state", request.State)
+		}
+		return oidcRedirectURL(request.RedirectURI, params), http.StatusFound, nil
+	}
+
+	if request.ResponseType != "code" {
+		return redirectError("unsupported_response_type", "only the code response type is supported")
+	}
+
+	// unknown scopes are dropped, openid is what makes this an OIDC request
+	scopes := []string{}
+	for _, scope := range strings.Fields(request.Scope) {
+		if slices.Contains(services.OIDCScopes, scope) && !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if !slices.Contains(scopes, "openid") {
+		return redirectError("invalid_scope", "the openid scope is required")
+	}
+
+	if request.CodeChallenge == "" && client.Public {
+		return redirectError("invalid_request", "public clients must use PKCE")
+	}
+	if request.CodeChallenge != "" && request.CodeChallengeMethod != "S256" {
+		return redirectError("invalid_request", "code_challenge_method must be S256")
+	}
+
+	claim := currentUserClaim(contx)
+	if claim == nil {
+		if contx.Request().Method != http.MethodGet {
+			return "", http.StatusUnauthorized, &models.OIDCError{Error: "login_required", ErrorDescription: "sign in and retry with the access token"}
+		}
+
+		// the login page sends the user back here, or posts the same parameters, once signed in
+		next := utils.OIDCIssuer() + "/oidc/authorize?" + contx.QueryString()
+		loginURL := configs.AppConfig.GetOrDefault("OIDC_LOGIN_URL", "http://localhost:3000/login")
+		return oidcRedirectURL(loginURL, url.Values{"next": {next}}), http.StatusFound, nil
+	}
+
+	userID, err := primitive.ObjectIDFromHex(claim.UserID)
+	if err != nil {
+		return "", http.StatusUnauthorized, &models.OIDCError{Error: "login_required", ErrorDescription: err.Error()}
+	}
+
+	authTime := time.Now()
+	if claim.IssuedAt != nil {
+		authTime = claim.IssuedAt.Time
+	}
+
+	code, err := services.HandlerAuthorizationCodeService.Create(tracer.Tracer, &models.AuthorizationCode{
+		ClientID:            client.ClientID,
+		UserID:              userID,
+		RedirectURI:         request.RedirectURI,
+		Scope:               strings.Join(scopes, " "),
+		Nonce:               request.Nonce,
+		CodeChallenge:       request.CodeChallenge,
+		CodeChallengeMethod: request.CodeChallengeMethod,
+		AuthTime:            authTime,
+	})
+	if err != nil {
+		return redirectError("server_error", "unable to issue an authorization code")
+	}
+
+	params := url.Values{"code": {code}}
+	if request.State != "" {
+		params.Set("state", request.State)
+	}
+	return oidcRedirectURL(request.RedirectURI, params), http.StatusFound, nil
+}
+
+// Authorize function for the browser facing authorization endpoint
+// @Summary OIDC Authorization
+// @Description Authorization code flow with PKCE, redirects to the login page when the user is not signed in
+// @Tags OIDC
+// @Produce json
+// @Param response_type query string true "code"
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string true "Registered redirect uri"
+// @Param scope query string true "openid plus profile, email, groups"
+// @Param state query string false "Opaque value returned to the client"
+// @Param nonce query string false "Value echoed in the id token"
+// @Param code_challenge query string false "PKCE challenge, required for public clients"
+// @Param code_challenge_method query string false "S256"
+// @Success 302
+// @Failure 400 {object} models.OIDCError
+// @Router /django_auth/oidc/authorize [get]
+func Authorize(contx echo.Context) error {
+	redirectTo, status, oidcErr := authorize(contx)
+	if oidcErr != nil {
+		return contx.JSON(status, oidcErr)
+	}
+	return contx.Redirect(status, redirectTo)
+}
+
+// AuthorizeWithToken function lets the login page finish authorization for the signed in user
+// @Summary OIDC Authorization for the login page
+// @Description Same parameters as the authorization endpoint, returns where to send the browser instead of redirecting
+// @Tags OIDC
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body models.OIDCAuthorizeRequest true "Authorization Request"
+// @Success 200 {object} models.OIDCAuthorizeResponse
+// @Failure 400 {object} models.OIDCError
+// @Failure 401 {object} models.OIDCError
+// @Router /django_auth/oidc/authorize [post]
+func AuthorizeWithToken(contx echo.Context) error {
+	redirectTo, status, oidcErr := authorize(contx)
+	if oidcErr != nil {
+		return contx.JSON(status, oidcErr)
+	}
+	return contx.JSON(http.StatusOK, models.OIDCAuthorizeResponse{RedirectTo: redirectTo})
+}
+
+// Token function exchanges an authorization code for an id token
+// @Summary OIDC Token
+// @Description Exchange an authorization code, clients authenticate with basic auth, form credentials or PKCE only
+// @Tags OIDC
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "authorization_code"
+// @Param code formData string true "Authorization code"
+// @Param redirect_uri formData string true "Redirect uri used for the code"
+// @Param client_id formData string false "Client ID"
+// @Param client_secret formData string false "Client secret"
+// @Param code_verifier formData string false "PKCE verifier"
+// @Success 200 {object} models.OIDCTokenResponse
+// @Failure 400 {object} models.OIDCError
+// @Failure 401 {object} models.OIDCError
+// @Router /django_auth/oidc/token [post]
+func Token(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// token responses must never be cached
+	contx.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	contx.Response().Header().Set("Pragma", "no-cache")
+
+	request := new(models.OIDCTokenRequest)
+	if err := contx.Bind(request); err != nil {
+		return contx.JSON(http.StatusBadRequest, models.OIDCError{Error: "invalid_request", ErrorDescription: err.Error()})
+	}
+
+	if request.GrantType != "authorization_code" {
+		return contx.JSON(http.StatusBadRequest, models.OIDCError{Error: "unsupported_grant_type"})
+	}
+
+	// client_secret_basic values are form encoded before being put in the header
+	clientID, clientSecret := request.ClientID, request.ClientSecret
+	if username, password, ok := contx.Request().BasicAuth(); ok {
+		clientID, _ = url.QueryUnescape(username)
+		clientSecret, _ = url.QueryUnescape(password)
+	}
+
+	client, err := services.HandlerOIDCClientService.FindByClientID(tracer.Tracer, clientID)
+	if err != nil || !services.VerifyClientSecret(client, clientSecret) {
+		contx.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="oidc"`)
+		return contx.JSON(http.StatusUnauthorized, models.OIDCError{Error: "invalid_client"})
+	}
+
+	code, err := services.HandlerAuthorizationCodeService.Consume(tracer.Tracer, request.Code, client.ClientID, request.RedirectURI)
+	if err != nil {
+		status, oidcErr := http.StatusInternalServerError, "server_error"
+		if errors.Is(err, services.ErrAuthorizationCodeInvalid) {
+			status, oidcErr = http.StatusBadRequest, "invalid_grant"
+		}
+		return contx.JSON(status, models.OIDCError{Error: oidcErr, ErrorDescription: err.Error()})
+	}
+
+	// a code issued with a challenge only works with its verifier, and the other way round
+	if code.CodeChallenge != "" || request.CodeVerifier != "" {
+		if !utils.VerifyPKCE(request.CodeVerifier, code.CodeChallenge, code.CodeChallengeMethod) {
+			return contx.JSON(http.StatusBadRequest, models.OIDCError{Error: "invalid_grant", ErrorDescription: "code_verifier does not match the code_challenge"})
+		}
+	}
+
+	tokens, err := services.HandlerUserService.IssueOIDCTokens(tracer.Tracer, code)
+	if err != nil {
+		if errors.Is(err, services.ErrInactiveUser) {
+			return contx.JSON(http.StatusBadRequest, models.OIDCError{Error: "invalid_grant", ErrorDescription: err.Error()})
+		}
+		return contx.JSON(http.StatusInternalServerError, models.OIDCError{Error: "server_error", ErrorDescription: err.Error()})
+	}
+
+	return contx.JSON(http.StatusOK, tokens)
+}
+
+// UserInfo function returns the claims of the user the access token was issued for
+// @Summary OIDC UserInfo
+// @Description Claims of the signed in user for the granted scopes, takes the OIDC access token as a bearer token
+// @Tags OIDC
+// @Produce json
+// @Param Authorization header string true "Bearer access token"
+// @Success 200 {object} map[string]any
+// @Failure 401 {object} models.OIDCError
+// @Router /django_auth/oidc/userinfo [get]
+func UserInfo(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	invalidToken := func(description string) error {
+		contx.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		return contx.JSON(http.StatusUnauthorized, models.OIDCError{Error: "invalid_token", ErrorDescription: description})
+	}
+
+	token, found := strings.CutPrefix(contx.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if !found {
+		return invalidToken("bearer token is missing")
+	}
+
+	claim, err := utils.ParseJWTToken(strings.TrimSpace(token), utils.OIDCAccessTokenType)
+	if err != nil {
+		return invalidToken(err.Error())
+	}
+
+	claims, err := services.HandlerUserService.OIDCUserClaims(tracer.Tracer, claim.UserID, claim.Scope)
+	if err != nil {
+		return invalidToken(err.Error())
+	}
+
+	return contx.JSON(http.StatusOK, claims)
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// RequestPasswordReset function to mail a password reset link
+// @Summary Request Password Reset
+// @Description Mail a reset link to the account with the email, the response is the same whether the email exists or not
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param reset body models.PasswordResetPost true "Password Reset"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /django_auth/password/reset [post]
+func RequestPasswordReset(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	reset := new(models.PasswordResetPost)
+
+	//first parse request data
+	if err := contx.Bind(&reset); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(reset); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// sending in the background so the response time does not tell whether the email exists
+	go func(ctx context.Context, email string) {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		if err := services.HandlerUserService.RequestPasswordReset(ctx, email); err != nil {
+			log.Printf("password reset request failed: %v", err)
+		}
+	}(context.WithoutCancel(tracer.Tracer), reset.Email)
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "If an account with that email exists, a password reset link has been sent.",
+		Data:    nil,
+	})
+}
+
+// ConfirmPasswordReset function to set a new password from a reset link
+// @Summary Confirm Password Reset
+// @Description Set a new password using the uid and token from the reset link, signs the user out everywhere
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param reset body models.PasswordResetConfirmPost true "Password Reset Confirmation"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{data=[]common.FieldError}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/password/reset/confirm [post]
+func ConfirmPasswordReset(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	reset := new(models.PasswordResetConfirmPost)
+
+	//first parse request data
+	if err := contx.Bind(&reset); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(reset); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	err := services.HandlerUserService.ResetPassword(tracer.Tracer, reset.UID, reset.Token, reset.NewPassword)
+	if err != nil {
+		var policyErr *utils.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			return passwordPolicyErrorResponse(contx, "new_password", policyErr)
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPasswordResetInvalid) {
+			status = http.StatusBadRequest
+		}
+		return contx.JSON(status, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Password has been reset, please login with the new password.",
+		Data:    nil,
+	})
+}
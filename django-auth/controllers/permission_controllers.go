@@ -1,12 +1,14 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/bushubdegefu/m-playground/common"
 	"github.com/bushubdegefu/m-playground/django-auth/models"
 	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
 	"github.com/bushubdegefu/m-playground/observe"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -91,6 +93,7 @@ func GetPermissions(contx echo.Context) error {
 // @Produce json
 // @Param permission_id path string true "Permission ID"
 // @Success 200 {object} common.ResponseHTTP{data=models.PermissionGet}
+// @Header 200 {string} ETag "version of the permission, send it back in If-Match to patch or delete only that version"
 // @Failure 404 {object} common.ResponseHTTP{}
 // @Router /django_auth/permission/{permission_id} [get]
 func GetPermissionByID(contx echo.Context) error {
@@ -108,6 +111,7 @@ func GetPermissionByID(contx echo.Context) error {
 			Message: err.Error(),
 		})
 	}
+	setETag(contx, permission.Version)
 
 	// Send paginated response
 	return contx.JSON(http.StatusOK, common.ResponseHTTP{
@@ -128,6 +132,7 @@ func GetPermissionByID(contx echo.Context) error {
 // @Param permission body models.PermissionPost true "Add Permission"
 // @Success 200 {object} common.ResponseHTTP{data=models.PermissionPost}
 // @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 409 {object} common.ResponseHTTP{data=[]common.FieldError}
 // @Failure 500 {object} common.ResponseHTTP{}
 // @Router /django_auth/permission [post]
 func PostPermission(contx echo.Context) error {
@@ -161,6 +166,10 @@ func PostPermission(contx echo.Context) error {
 	// post permission from service
 	permission, err := services.HandlerPermissionService.Create(tracer.Tracer, posted_permission)
 	if err != nil {
+		var conflict *services.ConflictError
+		if errors.As(err, &conflict) {
+			return conflictErrorResponse(contx, conflict)
+		}
 		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
@@ -184,8 +193,13 @@ func PostPermission(contx echo.Context) error {
 // @Produce json
 // @Param permission body models.PermissionPatch true "Patch Permission"
 // @Param permission_id path string true "Permission ID"
-// @Success 200 {object} common.ResponseHTTP{data=models.PermissionPatch}
+// @Param If-Match header string false "ETag of the permission as read, 412 when it changed since"
+// @Success 200 {object} common.ResponseHTTP{data=models.PermissionGet}
+// @Header 200 {string} ETag "version of the permission after the update"
 // @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 409 {object} common.ResponseHTTP{data=[]common.FieldError}
+// @Failure 412 {object} common.ResponseHTTP{}
 // @Failure 500 {object} common.ResponseHTTP{}
 // @Router /django_auth/permission/{permission_id} [patch]
 func PatchPermission(contx echo.Context) error {
@@ -218,16 +232,34 @@ func PatchPermission(contx echo.Context) error {
 		})
 	}
 
+	// the update only goes through while the permission is still the version the client read
+	expectedVersion, ok := ifMatchVersion(contx)
+	if !ok {
+		return preconditionFailedResponse(contx)
+	}
+
 	// patch permission from service
-	permission, err := services.HandlerPermissionService.Update(tracer.Tracer, patch_permission, id)
+	permission, err := services.HandlerPermissionService.Update(tracer.Tracer, patch_permission, id, expectedVersion)
 	if err != nil {
-		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+		var conflict *services.ConflictError
+		if errors.As(err, &conflict) {
+			return conflictErrorResponse(contx, conflict)
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPermissionNotFound) {
+			status = http.StatusNotFound
+		}
+		if errors.Is(err, services.ErrVersionMismatch) {
+			status = http.StatusPreconditionFailed
+		}
+		return contx.JSON(status, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
 		})
 	}
 
 	// return data if transaction is sucessfull
+	setETag(contx, permission.Version)
 	return contx.JSON(http.StatusOK, common.ResponseHTTP{
 		Success: true,
 		Message: "Permission updated successfully.",
@@ -237,14 +269,16 @@ func PatchPermission(contx echo.Context) error {
 
 // DeletePermissions function removes a permission by ID
 // @Summary Remove Permission by ID
-// @Description Remove permission by ID
+// @Description Move the permission to the trash, where it can be restored until TRASH_RETENTION_DAYS passed. Removed for good at once when SOFT_DELETE_ENABLED is false
 // @Tags Permissions
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param permission_id path string true "Permission ID"
+// @Param If-Match header string false "ETag of the permission as read, 412 when it changed since"
 // @Success 200 {object} common.ResponseHTTP{}
 // @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 412 {object} common.ResponseHTTP{}
 // @Failure 503 {object} common.ResponseHTTP{}
 // @Router /django_auth/permission/{permission_id} [delete]
 func DeletePermission(contx echo.Context) error {
@@ -255,9 +289,24 @@ func DeletePermission(contx echo.Context) error {
 	id := contx.Param("permission_id")
 
 	// delete permission from service
-	err := services.HandlerPermissionService.Delete(tracer.Tracer, id)
+	// claim of the caller set by the auth middleware
+	claim := contx.Get("user_claim").(*utils.UserClaim)
+
+	expectedVersion, ok := ifMatchVersion(contx)
+	if !ok {
+		return preconditionFailedResponse(contx)
+	}
+
+	err := services.HandlerPermissionService.Delete(tracer.Tracer, id, claim.UserID, expectedVersion)
 	if err != nil {
-		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPermissionNotFound) {
+			status = http.StatusNotFound
+		}
+		if errors.Is(err, services.ErrVersionMismatch) {
+			status = http.StatusPreconditionFailed
+		}
+		return contx.JSON(status, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
 		})
@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// Add Personal Access Token for the current user
+// @Summary Add a new Personal Access Token
+// @Description Create a token limited to a subset of your permissions, the token is only shown once
+// @Tags PersonalAccessTokens
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param token body models.PersonalAccessTokenPost true "Add Personal Access Token"
+// @Success 200 {object} common.ResponseHTTP{data=models.PersonalAccessTokenCreated}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 403 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/me/token [post]
+func PostMyAccessToken(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// claim of the caller set by the auth middleware
+	claim := contx.Get("user_claim").(*utils.UserClaim)
+
+	// a long lived token would outlast the impersonation
+	if claim.IsImpersonated() {
+		return contx.JSON(http.StatusForbidden, common.ResponseHTTP{
+			Success: false,
+			Message: "personal access tokens can not be created while impersonating",
+		})
+	}
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	posted_token := new(models.PersonalAccessTokenPost)
+
+	//first parse request data
+	if err := contx.Bind(&posted_token); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(posted_token); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if posted_token.ExpiresAt != nil && posted_token.ExpiresAt.Before(time.Now()) {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: "expires_at must be in the future",
+			Data:    nil,
+		})
+	}
+
+	// scopes can only narrow down what the user can already do
+	scope, err := services.HandlerPersonalAccessTokenService.UngrantableScope(tracer.Tracer, claim.UserID, posted_token.Scopes)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+	if scope != "" {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: fmt.Sprintf("scope %s is not one of your permissions", scope),
+			Data:    nil,
+		})
+	}
+
+	// post token from service
+	token, err := services.HandlerPersonalAccessTokenService.Create(tracer.Tracer, claim.UserID, posted_token)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// return data if transaction is sucessfull
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Personal access token created successfully, copy it now as it will not be shown again.",
+		Data:    token,
+	})
+}
+
+// Get Personal Access Tokens of the current user
+// @Summary Get my Personal Access Tokens
+// @Description List your personal access tokens
+// @Tags PersonalAccessTokens
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.ResponseHTTP{data=[]models.PersonalAccessTokenGet}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/me/token [get]
+func GetMyAccessTokens(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// claim of the caller set by the auth middleware
+	claim := contx.Get("user_claim").(*utils.UserClaim)
+
+	tokens, err := services.HandlerPersonalAccessTokenService.GetForUser(tracer.Tracer, claim.UserID)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// return value if transaction is sucessfull
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Success",
+		Data:    tokens,
+	})
+}
+
+// Revoke Personal Access Token of the current user
+// @Summary Revoke my Personal Access Token
+// @Description Revoke one of your personal access tokens
+// @Tags PersonalAccessTokens
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param token_id path string true "Token ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /django_auth/me/token/{token_id} [delete]
+func DeleteMyAccessToken(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// claim of the caller set by the auth middleware
+	claim := contx.Get("user_claim").(*utils.UserClaim)
+
+	// validate path params
+	token_id := contx.Param("token_id")
+
+	if err := services.HandlerPersonalAccessTokenService.Revoke(tracer.Tracer, token_id, claim.UserID); err != nil {
+		return contx.JSON(http.StatusNotFound, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// Return success respons
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Personal access token revoked successfully.",
+		Data:    nil,
+	})
+}
+
+// GetAccessTokens function to get every user's Personal Access Tokens with pagination
+// @Summary Get Personal Access Tokens
+// @Description Get Personal Access Tokens of all users
+// @Tags PersonalAccessTokens
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int true "page"
+// @Param size query int true "page size"
+// @Param user_id query string false "Filter by user optional field string"
+// @Success 200 {object} common.ResponsePagination{data=[]models.PersonalAccessTokenGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /django_auth/accesstoken [get]
+func GetAccessTokens(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	//  parsing Query Prameters
+	Page, _ := strconv.Atoi(contx.QueryParam("page"))
+	Limit, _ := strconv.Atoi(contx.QueryParam("size"))
+	//  checking if query parameters  are correct
+	if Page == 0 || Limit == 0 {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: "Not Allowed, Bad request",
+			Data:    nil,
+		})
+	}
+
+	// Prepare pagination model
+	pagination := models.Pagination{
+		Page: Page - 1, // assuming pages are 0-indexed in backend
+		Size: Limit,
+	}
+
+	// Fetch tokens from service
+	tokens, totalCount, err := services.HandlerPersonalAccessTokenService.Get(tracer.Tracer, pagination, contx.QueryParam("user_id"))
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// Send paginated response
+	return contx.JSON(http.StatusOK, common.ResponsePagination{
+		Success: true,
+		Message: "Success.",
+		Items:   tokens,
+		Total:   totalCount,
+		Page:    uint(Page),
+		Size:    uint(Limit),
+	})
+}
+
+// DeleteAccessToken function revokes any user's Personal Access Token
+// @Summary Revoke Personal Access Token by ID
+// @Description Revoke any user's personal access token
+// @Tags PersonalAccessTokens
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param token_id path string true "Token ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Router /django_auth/accesstoken/{token_id} [delete]
+func DeleteAccessToken(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validate path params
+	token_id := contx.Param("token_id")
+
+	if err := services.HandlerPersonalAccessTokenService.Revoke(tracer.Tracer, token_id, ""); err != nil {
+		return contx.JSON(http.StatusNotFound, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// Return success respons
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Personal access token revoked successfully.",
+		Data:    nil,
+	})
+}
@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// Register function to sign up a new user
+// @Summary Register
+// @Description Create an inactive account and mail a verification link, the account is activated once the link is used
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param registration body models.RegistrationPost true "Registration"
+// @Success 201 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{data=[]common.FieldError}
+// @Failure 403 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/register [post]
+func Register(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	registration := new(models.RegistrationPost)
+
+	//first parse request data
+	if err := contx.Bind(&registration); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(registration); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	user, err := services.HandlerUserService.Register(tracer.Tracer, registration)
+	if err != nil {
+		var policyErr *utils.PasswordPolicyError
+		var conflict *services.ConflictError
+		switch {
+		case errors.As(err, &policyErr):
+			return passwordPolicyErrorResponse(contx, "password", policyErr)
+		case errors.Is(err, services.ErrRegistrationDisabled):
+			return contx.JSON(http.StatusForbidden, common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+			})
+		case errors.Is(err, services.ErrEmailDomainNotAllowed):
+			return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    []common.FieldError{{Field: "email", Code: "email_domain_not_allowed", Message: err.Error()}},
+			})
+		case errors.As(err, &conflict):
+			// answering like a new account so sign up can not tell which usernames and emails are taken,
+			// the owner of a taken email is told by mail instead
+			if errors.Is(err, services.ErrEmailTaken) {
+				go func(ctx context.Context, email string) {
+					ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+					defer cancel()
+					if err := services.HandlerUserService.SendAccountExistsEmail(ctx, email); err != nil {
+						log.Printf("account exists mail failed: %v", err)
+					}
+				}(context.WithoutCancel(tracer.Tracer), registration.Email)
+			}
+			return registeredResponse(contx)
+		}
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// sending in the background, a failed mail can be retried with the resend endpoint
+	go func(ctx context.Context, user *models.User) {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		if err := services.HandlerUserService.SendVerificationEmail(ctx, user); err != nil {
+			log.Printf("verification mail to user %s failed: %v", user.ID.Hex(), err)
+		}
+	}(context.WithoutCancel(tracer.Tracer), user)
+
+	return registeredResponse(contx)
+}
+
+// the same answer whether the account was created or already existed
+func registeredResponse(contx echo.Context) error {
+	return contx.JSON(http.StatusCreated, common.ResponseHTTP{
+		Success: true,
+		Message: "Account created, please check your email to activate it.",
+		Data:    nil,
+	})
+}
+
+// VerifyEmail function to activate an account from a verification link
+// @Summary Verify Email
+// @Description Activate the account using the uid and token from the verification link
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param verification body models.EmailVerificationPost true "Email Verification"
+// @Success 200 {object} common.ResponseHTTP{data=models.UserGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/register/verify [post]
+func VerifyEmail(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	verification := new(models.EmailVerificationPost)
+
+	//first parse request data
+	if err := contx.Bind(&verification); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(verification); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	user, err := services.HandlerUserService.VerifyEmail(tracer.Tracer, verification.UID, verification.Token)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrEmailVerificationInvalid) {
+			status = http.StatusBadRequest
+		}
+		return contx.JSON(status, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Email verified, your account is now active.",
+		Data:    user,
+	})
+}
+
+// ResendVerification function to mail a new verification link
+// @Summary Resend Verification
+// @Description Mail a new verification link to an account waiting for verification, the response is the same whether the email exists or not
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param resend body models.VerificationResendPost true "Verification Resend"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /django_auth/register/resend [post]
+func ResendVerification(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validator initialization
+	validate := validator.New()
+
+	//validating post data
+	resend := new(models.VerificationResendPost)
+
+	//first parse request data
+	if err := contx.Bind(&resend); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// then validate structure
+	if err := validate.Struct(resend); err != nil {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	// sending in the background so the response time does not tell whether the email exists
+	go func(ctx context.Context, email string) {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		if err := services.HandlerUserService.ResendVerification(ctx, email); err != nil {
+			log.Printf("verification resend failed: %v", err)
+		}
+	}(context.WithoutCancel(tracer.Tracer), resend.Email)
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "If an account with that email is waiting for verification, a new link has been sent.",
+		Data:    nil,
+	})
+}
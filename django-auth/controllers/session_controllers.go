@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/labstack/echo/v4"
+)
+
+// GetSession function to get the cookie session of the caller
+// @Summary Get Session
+// @Description Get the user of the cookie session and the csrf token to send in X-CSRF-Token with unsafe requests
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.ResponseHTTP{data=models.SessionInfo}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 401 {object} common.ResponseHTTP{}
+// @Router /django_auth/session [get]
+func GetSession(contx echo.Context) error {
+	// session of the caller set by the auth middleware
+	session, ok := contx.Get("session").(*models.Session)
+	if !ok {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: "request is not authenticated with a session cookie",
+		})
+	}
+
+	claim := contx.Get("user_claim").(*utils.UserClaim)
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Success",
+		Data: models.SessionInfo{
+			UserID:    claim.UserID,
+			Username:  claim.Username,
+			CSRFToken: session.CSRFToken,
+			ExpiresAt: session.ExpiresAt,
+		},
+	})
+}
+
+// login endpoints start a cookie session instead of returning tokens with ?session=true
+func wantsSession(contx echo.Context) bool {
+	return contx.QueryParam("session") == "true"
+}
+
+// a page on another site could post its own credentials to login and plant that session in
+// the browser of a visitor, the csrf middleware only guards requests that already have a session.
+// Session logins are only taken from this host or an origin listed in SESSION_TRUSTED_ORIGINS
+func trustedSessionOrigin(contx echo.Context) bool {
+	origin := contx.Request().Header.Get(echo.HeaderOrigin)
+	if origin == "" {
+		referer, err := url.Parse(contx.Request().Referer())
+		if err != nil || referer.Host == "" {
+			return false
+		}
+		origin = referer.Scheme + "://" + referer.Host
+	}
+
+	if strings.EqualFold(origin, contx.Scheme()+"://"+contx.Request().Host) {
+		return true
+	}
+	for _, trusted := range strings.Split(configs.AppConfig.GetOrDefault("SESSION_TRUSTED_ORIGINS", ""), ",") {
+		if trusted = strings.TrimSpace(trusted); trusted != "" && strings.EqualFold(origin, trusted) {
+			return true
+		}
+	}
+	return false
+}
+
+// builds a session cookie from SESSION_COOKIE_* settings, a negative max age removes it
+func sessionCookie(name string, value string, maxAge int, httpOnly bool) *http.Cookie {
+	sameSite := http.SameSiteLaxMode
+	if strings.EqualFold(configs.AppConfig.GetOrDefault("SESSION_COOKIE_SAMESITE", "lax"), "strict") {
+		sameSite = http.SameSiteStrictMode
+	}
+
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   configs.AppConfig.GetOrDefault("SESSION_COOKIE_DOMAIN", ""),
+		MaxAge:   maxAge,
+		Secure:   configs.AppConfig.GetOrDefault("SESSION_COOKIE_SECURE", "true") == "true",
+		HttpOnly: httpOnly,
+		SameSite: sameSite,
+	}
+}
+
+// starts a cookie session for a completed login, the csrf cookie is readable by the frontend
+func startSession(contx echo.Context, claim utils.UserClaim, recoveryCodes []string) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	if !trustedSessionOrigin(contx) {
+		return contx.JSON(http.StatusForbidden, common.ResponseHTTP{
+			Success: false,
+			Message: "sessions can only be started from a trusted origin",
+		})
+	}
+
+	key, session, err := services.HandlerSessionService.Create(tracer.Tracer, claim.UserID, contx.RealIP(), contx.Request().UserAgent())
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	maxAge := int(time.Until(session.ExpiresAt).Seconds())
+	contx.SetCookie(sessionCookie(services.SessionCookieName(), key, maxAge, true))
+	contx.SetCookie(sessionCookie(services.CSRFCookieName, session.CSRFToken, maxAge, false))
+
+	info := &models.SessionInfo{
+		UserID:    claim.UserID,
+		Username:  claim.Username,
+		CSRFToken: session.CSRFToken,
+		ExpiresAt: session.ExpiresAt,
+	}
+
+	if recoveryCodes != nil {
+		return contx.JSON(http.StatusOK, common.ResponseHTTP{
+			Success: true,
+			Message: "MFA enabled, store the recovery codes somewhere safe as they will not be shown again.",
+			Data: models.MFARecoveryCodes{
+				RecoveryCodes: recoveryCodes,
+				Session:       info,
+			},
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Login successful.",
+		Data:    info,
+	})
+}
+
+// ends the cookie session of the caller and removes its cookies
+func endSession(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	session := contx.Get("session").(*models.Session)
+	if err := services.HandlerSessionService.Revoke(tracer.Tracer, session); err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	contx.SetCookie(sessionCookie(services.SessionCookieName(), "", -1, true))
+	contx.SetCookie(sessionCookie(services.CSRFCookieName, "", -1, false))
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Logged out successfully.",
+		Data:    nil,
+	})
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/labstack/echo/v4"
+)
+
+// GetSigningKeys function to list the token signing keys
+// @Summary Get Signing Keys
+// @Description List the signing keys of the key ring with their state, private keys are never returned
+// @Tags SigningKeys
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.ResponseHTTP{data=[]models.SigningKeyGet}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/signingkey [get]
+func GetSigningKeys(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	keys, err := services.HandlerSigningKeyService.Get(tracer.Tracer)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Success",
+		Data:    keys,
+	})
+}
+
+// RotateSigningKeys function to rotate the token signing keys
+// @Summary Rotate Signing Keys
+// @Description Promote the next key to active, retire the active key and create a new next key
+// @Tags SigningKeys
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.ResponseHTTP{data=[]models.SigningKeyGet}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/signingkey/rotate [post]
+func RotateSigningKeys(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	keys, err := services.HandlerSigningKeyService.Rotate(tracer.Tracer)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Signing keys rotated successfully.",
+		Data:    keys,
+	})
+}
@@ -0,0 +1,298 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/observe"
+	"github.com/labstack/echo/v4"
+)
+
+// GetUserTrash function to get the users in the trash with pagination
+// @Summary Get User Trash
+// @Description Get deleted users that can still be restored, most recently deleted first
+// @Tags Trash
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param page query int true "page"
+// @Param size query int true "page size"
+// @Success 200 {object} common.ResponsePagination{data=[]models.UserGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /django_auth/trash/user [get]
+func GetUserTrash(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	//  parsing Query Prameters
+	Page, _ := strconv.Atoi(contx.QueryParam("page"))
+	Limit, _ := strconv.Atoi(contx.QueryParam("size"))
+	//  checking if query parameters  are correct
+	if Page == 0 || Limit == 0 {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: "Not Allowed, Bad request",
+			Data:    nil,
+		})
+	}
+
+	// Prepare pagination model
+	pagination := models.Pagination{
+		Page: Page - 1, // assuming pages are 0-indexed in backend
+		Size: Limit,
+	}
+
+	users, totalCount, err := services.HandlerUserService.GetTrash(tracer.Tracer, pagination)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// Send paginated response
+	return contx.JSON(http.StatusOK, common.ResponsePagination{
+		Success: true,
+		Message: "Success.",
+		Items:   users,
+		Total:   totalCount,
+		Page:    uint(Page),
+		Size:    uint(Limit),
+	})
+}
+
+// RestoreUser function to take a user out of the trash
+// @Summary Restore User
+// @Description Restore a deleted user with its memberships and grants
+// @Tags Trash
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 409 {object} common.ResponseHTTP{data=[]common.FieldError}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/trash/user/{user_id}/restore [post]
+func RestoreUser(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validate path params
+	id := contx.Param("user_id")
+
+	if err := services.HandlerUserService.Restore(tracer.Tracer, id); err != nil {
+		var conflict *services.ConflictError
+		if errors.As(err, &conflict) {
+			return conflictErrorResponse(contx, conflict)
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		return contx.JSON(status, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "User restored successfully.",
+		Data:    nil,
+	})
+}
+
+// GetGroupTrash function to get the groups in the trash with pagination
+// @Summary Get Group Trash
+// @Description Get deleted groups that can still be restored, most recently deleted first
+// @Tags Trash
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param page query int true "page"
+// @Param size query int true "page size"
+// @Success 200 {object} common.ResponsePagination{data=[]models.GroupGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /django_auth/trash/group [get]
+func GetGroupTrash(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	//  parsing Query Prameters
+	Page, _ := strconv.Atoi(contx.QueryParam("page"))
+	Limit, _ := strconv.Atoi(contx.QueryParam("size"))
+	//  checking if query parameters  are correct
+	if Page == 0 || Limit == 0 {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: "Not Allowed, Bad request",
+			Data:    nil,
+		})
+	}
+
+	// Prepare pagination model
+	pagination := models.Pagination{
+		Page: Page - 1, // assuming pages are 0-indexed in backend
+		Size: Limit,
+	}
+
+	groups, totalCount, err := services.HandlerGroupService.GetTrash(tracer.Tracer, pagination)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// Send paginated response
+	return contx.JSON(http.StatusOK, common.ResponsePagination{
+		Success: true,
+		Message: "Success.",
+		Items:   groups,
+		Total:   totalCount,
+		Page:    uint(Page),
+		Size:    uint(Limit),
+	})
+}
+
+// RestoreGroup function to take a group out of the trash
+// @Summary Restore Group
+// @Description Restore a deleted group with its memberships and grants
+// @Tags Trash
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param group_id path string true "Group ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 409 {object} common.ResponseHTTP{data=[]common.FieldError}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/trash/group/{group_id}/restore [post]
+func RestoreGroup(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validate path params
+	id := contx.Param("group_id")
+
+	if err := services.HandlerGroupService.Restore(tracer.Tracer, id); err != nil {
+		var conflict *services.ConflictError
+		if errors.As(err, &conflict) {
+			return conflictErrorResponse(contx, conflict)
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrGroupNotFound) {
+			status = http.StatusNotFound
+		}
+		return contx.JSON(status, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Group restored successfully.",
+		Data:    nil,
+	})
+}
+
+// GetPermissionTrash function to get the permissions in the trash with pagination
+// @Summary Get Permission Trash
+// @Description Get deleted permissions that can still be restored, most recently deleted first
+// @Tags Trash
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param page query int true "page"
+// @Param size query int true "page size"
+// @Success 200 {object} common.ResponsePagination{data=[]models.PermissionGet}
+// @Failure 400 {object} common.ResponseHTTP{}
+// @Router /django_auth/trash/permission [get]
+func GetPermissionTrash(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	//  parsing Query Prameters
+	Page, _ := strconv.Atoi(contx.QueryParam("page"))
+	Limit, _ := strconv.Atoi(contx.QueryParam("size"))
+	//  checking if query parameters  are correct
+	if Page == 0 || Limit == 0 {
+		return contx.JSON(http.StatusBadRequest, common.ResponseHTTP{
+			Success: false,
+			Message: "Not Allowed, Bad request",
+			Data:    nil,
+		})
+	}
+
+	// Prepare pagination model
+	pagination := models.Pagination{
+		Page: Page - 1, // assuming pages are 0-indexed in backend
+		Size: Limit,
+	}
+
+	permissions, totalCount, err := services.HandlerPermissionService.GetTrash(tracer.Tracer, pagination)
+	if err != nil {
+		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	// Send paginated response
+	return contx.JSON(http.StatusOK, common.ResponsePagination{
+		Success: true,
+		Message: "Success.",
+		Items:   permissions,
+		Total:   totalCount,
+		Page:    uint(Page),
+		Size:    uint(Limit),
+	})
+}
+
+// RestorePermission function to take a permission out of the trash
+// @Summary Restore Permission
+// @Description Restore a deleted permission with its memberships and grants
+// @Tags Trash
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param permission_id path string true "Permission ID"
+// @Success 200 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 409 {object} common.ResponseHTTP{data=[]common.FieldError}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/trash/permission/{permission_id}/restore [post]
+func RestorePermission(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validate path params
+	id := contx.Param("permission_id")
+
+	if err := services.HandlerPermissionService.Restore(tracer.Tracer, id); err != nil {
+		var conflict *services.ConflictError
+		if errors.As(err, &conflict) {
+			return conflictErrorResponse(contx, conflict)
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPermissionNotFound) {
+			status = http.StatusNotFound
+		}
+		return contx.JSON(status, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Permission restored successfully.",
+		Data:    nil,
+	})
+}
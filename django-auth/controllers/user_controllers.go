@@ -1,12 +1,14 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/bushubdegefu/m-playground/common"
 	"github.com/bushubdegefu/m-playground/django-auth/models"
 	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
 	"github.com/bushubdegefu/m-playground/observe"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -93,6 +95,7 @@ func GetUsers(contx echo.Context) error {
 // @Produce json
 // @Param user_id path string true "User ID"
 // @Success 200 {object} common.ResponseHTTP{data=models.UserGet}
+// @Header 200 {string} ETag "version of the user, send it back in If-Match to patch or delete only that version"
 // @Failure 404 {object} common.ResponseHTTP{}
 // @Router /django_auth/user/{user_id} [get]
 func GetUserByID(contx echo.Context) error {
@@ -110,6 +113,7 @@ func GetUserByID(contx echo.Context) error {
 			Message: err.Error(),
 		})
 	}
+	setETag(contx, user.Version)
 
 	// Send paginated response
 	return contx.JSON(http.StatusOK, common.ResponseHTTP{
@@ -129,7 +133,8 @@ func GetUserByID(contx echo.Context) error {
 // @Produce json
 // @Param user body models.UserPost true "Add User"
 // @Success 200 {object} common.ResponseHTTP{data=models.UserPost}
-// @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 400 {object} common.ResponseHTTP{data=[]common.FieldError}
+// @Failure 409 {object} common.ResponseHTTP{data=[]common.FieldError}
 // @Failure 500 {object} common.ResponseHTTP{}
 // @Router /django_auth/user [post]
 func PostUser(contx echo.Context) error {
@@ -163,6 +168,14 @@ func PostUser(contx echo.Context) error {
 	// post user from service
 	user, err := services.HandlerUserService.Create(tracer.Tracer, posted_user)
 	if err != nil {
+		var policyErr *utils.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			return passwordPolicyErrorResponse(contx, "password", policyErr)
+		}
+		var conflict *services.ConflictError
+		if errors.As(err, &conflict) {
+			return conflictErrorResponse(contx, conflict)
+		}
 		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
@@ -186,8 +199,13 @@ func PostUser(contx echo.Context) error {
 // @Produce json
 // @Param user body models.UserPatch true "Patch User"
 // @Param user_id path string true "User ID"
-// @Success 200 {object} common.ResponseHTTP{data=models.UserPatch}
-// @Failure 400 {object} common.ResponseHTTP{}
+// @Param If-Match header string false "ETag of the user as read, 412 when it changed since"
+// @Success 200 {object} common.ResponseHTTP{data=models.UserGet}
+// @Header 200 {string} ETag "version of the user after the update"
+// @Failure 400 {object} common.ResponseHTTP{data=[]common.FieldError}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 409 {object} common.ResponseHTTP{data=[]common.FieldError}
+// @Failure 412 {object} common.ResponseHTTP{}
 // @Failure 500 {object} common.ResponseHTTP{}
 // @Router /django_auth/user/{user_id} [patch]
 func PatchUser(contx echo.Context) error {
@@ -220,16 +238,38 @@ func PatchUser(contx echo.Context) error {
 		})
 	}
 
+	// the update only goes through while the user is still the version the client read
+	expectedVersion, ok := ifMatchVersion(contx)
+	if !ok {
+		return preconditionFailedResponse(contx)
+	}
+
 	// patch user from service
-	user, err := services.HandlerUserService.Update(tracer.Tracer, patch_user, id)
+	user, err := services.HandlerUserService.Update(tracer.Tracer, patch_user, id, expectedVersion)
 	if err != nil {
-		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+		var policyErr *utils.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			return passwordPolicyErrorResponse(contx, "password", policyErr)
+		}
+		var conflict *services.ConflictError
+		if errors.As(err, &conflict) {
+			return conflictErrorResponse(contx, conflict)
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		if errors.Is(err, services.ErrVersionMismatch) {
+			status = http.StatusPreconditionFailed
+		}
+		return contx.JSON(status, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
 		})
 	}
 
 	// return data if transaction is sucessfull
+	setETag(contx, user.Version)
 	return contx.JSON(http.StatusOK, common.ResponseHTTP{
 		Success: true,
 		Message: "User updated successfully.",
@@ -239,14 +279,16 @@ func PatchUser(contx echo.Context) error {
 
 // DeleteUsers function removes a user by ID
 // @Summary Remove User by ID
-// @Description Remove user by ID
+// @Description Move the user to the trash, where it can be restored until TRASH_RETENTION_DAYS passed. Removed for good at once when SOFT_DELETE_ENABLED is false
 // @Tags Users
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param user_id path string true "User ID"
+// @Param If-Match header string false "ETag of the user as read, 412 when it changed since"
 // @Success 200 {object} common.ResponseHTTP{}
 // @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 412 {object} common.ResponseHTTP{}
 // @Failure 503 {object} common.ResponseHTTP{}
 // @Router /django_auth/user/{user_id} [delete]
 func DeleteUser(contx echo.Context) error {
@@ -257,9 +299,24 @@ func DeleteUser(contx echo.Context) error {
 	id := contx.Param("user_id")
 
 	// delete user from service
-	err := services.HandlerUserService.Delete(tracer.Tracer, id)
+	// claim of the caller set by the auth middleware
+	claim := contx.Get("user_claim").(*utils.UserClaim)
+
+	expectedVersion, ok := ifMatchVersion(contx)
+	if !ok {
+		return preconditionFailedResponse(contx)
+	}
+
+	err := services.HandlerUserService.Delete(tracer.Tracer, id, claim.UserID, expectedVersion)
 	if err != nil {
-		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		if errors.Is(err, services.ErrVersionMismatch) {
+			status = http.StatusPreconditionFailed
+		}
+		return contx.JSON(status, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
 		})
@@ -286,6 +343,7 @@ func DeleteUser(contx echo.Context) error {
 // @Param permission_id path string true "Permission ID"
 // @Param user_id path string true "User ID"
 // @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
 // @Router /django_auth/userpermission/{permission_id}/{user_id} [post]
 func AddPermissionToUser(contx echo.Context) error {
 	//  Geting tracer
@@ -299,7 +357,11 @@ func AddPermissionToUser(contx echo.Context) error {
 
 	err := services.HandlerUserService.AddUserToPermission(tracer.Tracer, user_id, permission_id)
 	if err != nil {
-		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUserNotFound) || errors.Is(err, services.ErrPermissionNotFound) {
+			status = http.StatusNotFound
+		}
+		return contx.JSON(status, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
 		})
@@ -324,6 +386,7 @@ func AddPermissionToUser(contx echo.Context) error {
 // @Param user_id path string true "User ID"
 // @Success 200 {object} common.ResponseHTTP{data=models.UserPost}
 // @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
 // @Failure 500 {object} common.ResponseHTTP{}
 // @Router /django_auth/userpermission/{permission_id}/{user_id} [delete]
 func DeletePermissionFromUser(contx echo.Context) error {
@@ -339,7 +402,11 @@ func DeletePermissionFromUser(contx echo.Context) error {
 	// removing PermissionFromUser
 	err := services.HandlerUserService.RemoveUserFromPermission(tracer.Tracer, user_id, permission_id)
 	if err != nil {
-		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUserNotFound) || errors.Is(err, services.ErrPermissionNotFound) {
+			status = http.StatusNotFound
+		}
+		return contx.JSON(status, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
 		})
@@ -450,6 +517,45 @@ func GetAllPermissionsOfUsers(contx echo.Context) error {
 	})
 }
 
+// Get Effective Permissions of User
+// @Summary Get User Effective Permissions
+// @Description Get every permission the user holds directly or through a group, with the sources granting it
+// @Tags PermissionUsers
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Success 200 {object} common.ResponseHTTP{data=models.EffectivePermissions}
+// @Failure 404 {object} common.ResponseHTTP{}
+// @Failure 500 {object} common.ResponseHTTP{}
+// @Router /django_auth/user/{user_id}/effective-permissions [get]
+func GetEffectivePermissionsOfUser(contx echo.Context) error {
+	//  Geting tracer
+	tracer := contx.Get("tracer").(*observe.RouteTracer)
+
+	// validate path params
+	user_id := contx.Param("user_id")
+
+	// Fetch permissions from service
+	permissions, err := services.HandlerUserService.ExplainEffectivePermissions(tracer.Tracer, user_id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		return contx.JSON(status, common.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return contx.JSON(http.StatusOK, common.ResponseHTTP{
+		Success: true,
+		Message: "Success",
+		Data:    permissions,
+	})
+}
+
 // Get Permissions of User Not Complement
 // @Summary Get User to Permission Not Complement
 // @Description Get Permission User Not Complement
@@ -498,6 +604,7 @@ func GetPermissionComplementUsers(contx echo.Context) error {
 // @Param group_id path string true "Group ID"
 // @Param user_id path string true "User ID"
 // @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
 // @Router /django_auth/usergroup/{group_id}/{user_id} [post]
 func AddGroupToUser(contx echo.Context) error {
 	//  Geting tracer
@@ -511,7 +618,11 @@ func AddGroupToUser(contx echo.Context) error {
 
 	err := services.HandlerUserService.AddUserToGroup(tracer.Tracer, user_id, group_id)
 	if err != nil {
-		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUserNotFound) || errors.Is(err, services.ErrGroupNotFound) {
+			status = http.StatusNotFound
+		}
+		return contx.JSON(status, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
 		})
@@ -536,6 +647,7 @@ func AddGroupToUser(contx echo.Context) error {
 // @Param user_id path string true "User ID"
 // @Success 200 {object} common.ResponseHTTP{data=models.UserPost}
 // @Failure 400 {object} common.ResponseHTTP{}
+// @Failure 404 {object} common.ResponseHTTP{}
 // @Failure 500 {object} common.ResponseHTTP{}
 // @Router /django_auth/usergroup/{group_id}/{user_id} [delete]
 func DeleteGroupFromUser(contx echo.Context) error {
@@ -551,7 +663,11 @@ func DeleteGroupFromUser(contx echo.Context) error {
 	// removing GroupFromUser
 	err := services.HandlerUserService.RemoveUserFromGroup(tracer.Tracer, user_id, group_id)
 	if err != nil {
-		return contx.JSON(http.StatusInternalServerError, common.ResponseHTTP{
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUserNotFound) || errors.Is(err, services.ErrGroupNotFound) {
+			status = http.StatusNotFound
+		}
+		return contx.JSON(status, common.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
 		})
@@ -3,6 +3,8 @@ package django_auth
 import (
 	"fmt"
 	"github.com/bushubdegefu/m-playground/database"
+	"github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
 	"github.com/bushubdegefu/m-playground/observe"
 	"github.com/labstack/echo/v4"
 	"go.opentelemetry.io/otel/attribute"
@@ -13,7 +15,28 @@ func otelechospanstarter(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
 		routeName := ctx.Path() + "_" + strings.ToLower(ctx.Request().Method)
 		tracer, span := observe.EchoAppSpanner(ctx, fmt.Sprintf("%v-root", routeName))
-		ctx.Set("tracer", &observe.RouteTracer{Tracer: tracer, Span: span})
+
+		// changes made by the request are audited with who made them, through which route and in which trace
+		actor := services.AuditActor{RouteName: ctx.Request().Header.Get("route-name")}
+		if span.SpanContext().HasTraceID() {
+			actor.TraceID = span.SpanContext().TraceID().String()
+		}
+
+		// the auth middleware ran first, flagging requests made during an impersonation
+		if claim, ok := ctx.Get("user_claim").(*utils.UserClaim); ok {
+			actor.UserID = claim.UserID
+			actor.Username = claim.Username
+			if claim.IsImpersonated() {
+				actor.ImpersonatorID = claim.Act.UserID
+				span.SetAttributes(
+					attribute.Bool("impersonation", true),
+					attribute.String("impersonation.user_id", claim.UserID),
+					attribute.String("impersonation.actor_id", claim.Act.UserID),
+					attribute.String("impersonation.actor_username", claim.Act.Username),
+				)
+			}
+		}
+		ctx.Set("tracer", &observe.RouteTracer{Tracer: services.WithAuditActor(tracer, actor), Span: span})
 
 		// Process request
 		err := next(ctx)
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// audited kinds of change
+const (
+	AuditActionCreate         = "create"
+	AuditActionUpdate         = "update"
+	AuditActionDelete         = "delete"
+	AuditActionRestore        = "restore"
+	AuditActionPurge          = "purge"
+	AuditActionAddRelation    = "add_relation"
+	AuditActionRemoveRelation = "remove_relation"
+)
+
+// entity of entries about object level grants, users, groups and permissions are named by their object type
+const AuditEntityObjectPermission = "object_permission"
+
+// AuditChange model info
+// @Description before and after value of one field, secrets are redacted
+type AuditChange struct {
+	Field  string      `bson:"field" json:"field"`
+	Before interface{} `bson:"before,omitempty" json:"before,omitempty"`
+	After  interface{} `bson:"after,omitempty" json:"after,omitempty"`
+}
+
+// AuditLog Database model info
+// @Description one change of a user, group or permission, entries are only ever inserted
+type AuditLog struct {
+	ID             primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	ActorID        string             `bson:"actor_id,omitempty" json:"actor_id,omitempty"`
+	ActorUsername  string             `bson:"actor_username,omitempty" json:"actor_username,omitempty"`
+	ImpersonatorID string             `bson:"impersonator_id,omitempty" json:"impersonator_id,omitempty"`
+	RouteName      string             `bson:"route_name,omitempty" json:"route_name,omitempty"`
+	TraceID        string             `bson:"trace_id,omitempty" json:"trace_id,omitempty"`
+	Action         string             `bson:"action" json:"action"`
+	Entity         string             `bson:"entity" json:"entity"`
+	EntityID       string             `bson:"entity_id" json:"entity_id"`
+	Changes        []AuditChange      `bson:"changes" json:"changes"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
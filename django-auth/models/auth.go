@@ -0,0 +1,138 @@
+package models
+
+// LoginPost model info
+// @Description LoginPost type information
+type LoginPost struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// TokenResponse model info
+// @Description TokenResponse type information
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// MFAChallenge model info
+// @Description returned by login instead of tokens when a second factor is needed
+type MFAChallenge struct {
+	MFARequired           bool   `json:"mfa_required"`
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required"`
+	MFAToken              string `json:"mfa_token"`
+}
+
+// MFALoginPost model info
+// @Description MFALoginPost type information, code is a TOTP code or a recovery code
+type MFALoginPost struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// MFAEnrollPost model info
+// @Description MFAEnrollPost type information, mfa_token is only needed during login
+type MFAEnrollPost struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+// MFACodePost model info
+// @Description MFACodePost type information
+type MFACodePost struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// MFAEnrollment model info
+// @Description secret to add to an authenticator app, uri can be rendered as a QR code
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// MFARecoveryCodes model info
+// @Description one time recovery codes, only shown once
+type MFARecoveryCodes struct {
+	RecoveryCodes []string       `json:"recovery_codes"`
+	Tokens        *TokenResponse `json:"tokens,omitempty"`
+	Session       *SessionInfo   `json:"session,omitempty"`
+}
+
+// PasswordResetPost model info
+// @Description PasswordResetPost type information
+type PasswordResetPost struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// PasswordResetConfirmPost model info
+// @Description uid and token come from the reset link
+type PasswordResetConfirmPost struct {
+	UID         string `json:"uid" validate:"required"`
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+// RegistrationPost model info
+// @Description RegistrationPost type information
+type RegistrationPost struct {
+	Username  string `json:"username" validate:"required"`
+	Email     string `json:"email" validate:"required,email"`
+	Password  string `json:"password" validate:"required"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// EmailVerificationPost model info
+// @Description uid and token come from the verification link
+type EmailVerificationPost struct {
+	UID   string `json:"uid" validate:"required"`
+	Token string `json:"token" validate:"required"`
+}
+
+// VerificationResendPost model info
+// @Description VerificationResendPost type information
+type VerificationResendPost struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ImpersonationResponse model info
+// @Description short lived access token acting as the user, there is no refresh token
+type ImpersonationResponse struct {
+	AccessToken    string `json:"access_token"`
+	TokenType      string `json:"token_type"`
+	ExpiresIn      int64  `json:"expires_in"`
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	ImpersonatorID string `json:"impersonator_id"`
+}
+
+// AuthzCheckItem model info
+// @Description permission codename to check, with object_id object level grants are checked too
+type AuthzCheckItem struct {
+	Codename string `json:"codename" validate:"required"`
+	ObjectID string `json:"object_id,omitempty"`
+}
+
+// AuthzCheckPost model info
+// @Description either user_id or token (access token or personal access token) with up to 100 checks
+type AuthzCheckPost struct {
+	UserID string           `json:"user_id" validate:"required_without=Token,excluded_with=Token"`
+	Token  string           `json:"token" validate:"required_without=UserID"`
+	Checks []AuthzCheckItem `json:"checks" validate:"required,min=1,max=100,dive"`
+}
+
+// AuthzDecision model info
+// @Description allowed or denied with the reason, in the order of the checks
+type AuthzDecision struct {
+	Codename string `json:"codename"`
+	ObjectID string `json:"object_id,omitempty"`
+	Allowed  bool   `json:"allowed"`
+	Reason   string `json:"reason"`
+}
+
+// AuthzCheckResponse model info
+// @Description AuthzCheckResponse type information
+type AuthzCheckResponse struct {
+	UserID  string          `json:"user_id"`
+	Results []AuthzDecision `json:"results"`
+}
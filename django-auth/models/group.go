@@ -12,8 +12,16 @@ type Group struct {
 	ID            primitive.ObjectID   `bson:"_id,omitzero" json:"id,omitzero"`
 	Name          string               `bson:"name,omitzero" json:"name,omitzero"`
 	PermissionIDs []primitive.ObjectID `bson:"permission_ids,omitzero" json:"permission_ids,omitzero"`
-	CreatedAt     time.Time            `bson:"created_at,omitempty"`
-	UpdatedAt     time.Time            `bson:"updated_at,omitempty"`
+
+	// counts up with every change, sent as the ETag and checked against If-Match
+	Version int64 `bson:"version" json:"version"`
+
+	// set while the document is in the trash, deleted_by is the id of the user who deleted it
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy string     `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at,omitempty"`
+	UpdatedAt time.Time `bson:"updated_at,omitempty"`
 }
 
 // GroupPost model info
@@ -28,8 +36,16 @@ type GroupGet struct {
 	ID            primitive.ObjectID   `bson:"_id,omitzero" json:"id,omitzero"`
 	Name          string               `bson:"name,omitzero" json:"name,omitzero"`
 	PermissionIDs []primitive.ObjectID `bson:"permission_ids,omitzero" json:"permission_ids,omitzero"`
-	CreatedAt     time.Time            `bson:"created_at,omitempty"`
-	UpdatedAt     time.Time            `bson:"updated_at,omitempty"`
+
+	// counts up with every change, sent as the ETag and checked against If-Match
+	Version int64 `bson:"version" json:"version"`
+
+	// set while the document is in the trash, deleted_by is the id of the user who deleted it
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy string     `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at,omitempty"`
+	UpdatedAt time.Time `bson:"updated_at,omitempty"`
 }
 
 // GroupPut model info
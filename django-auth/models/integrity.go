@@ -0,0 +1,18 @@
+package models
+
+// OrphanReference model info
+// @Description a document referencing ids of users, groups or permissions that no longer exist
+type OrphanReference struct {
+	Collection string   `json:"collection"`
+	DocumentID string   `json:"document_id"`
+	Field      string   `json:"field"`
+	MissingIDs []string `json:"missing_ids"`
+}
+
+// OrphanReport model info
+// @Description repaired tells whether the references were removed or only reported
+type OrphanReport struct {
+	References []OrphanReference `json:"references"`
+	Total      int               `json:"total"`
+	Repaired   bool              `json:"repaired"`
+}
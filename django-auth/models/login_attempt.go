@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LoginAttempt Database model info
+// @Description failed login counter for a username or a client ip, dropped once expires_at passes
+type LoginAttempt struct {
+	ID            primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	Key           string             `bson:"key,omitzero" json:"key,omitzero"`
+	Failures      int                `bson:"failures,omitzero" json:"failures"`
+	LastFailureAt time.Time          `bson:"last_failure_at,omitempty" json:"last_failure_at,omitzero"`
+	LockedUntil   time.Time          `bson:"locked_until,omitempty" json:"locked_until,omitzero"`
+	ExpiresAt     time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitzero"`
+}
+
+// LoginUnlockPost model info
+// @Description clears the failed login counters of a username, an ip or both
+type LoginUnlockPost struct {
+	Username string `json:"username" validate:"required_without=IP"`
+	IP       string `json:"ip" validate:"omitempty,ip"`
+}
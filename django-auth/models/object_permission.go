@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// kinds of documents a permission can be granted on
+const (
+	ObjectTypeUser       = "user"
+	ObjectTypeGroup      = "group"
+	ObjectTypePermission = "permission"
+)
+
+// ObjectPermission Database model info
+// @Description grants a user or a group a permission on one document only, like django-guardian
+type ObjectPermission struct {
+	ID           primitive.ObjectID  `bson:"_id,omitzero" json:"id,omitzero"`
+	PermissionID primitive.ObjectID  `bson:"permission_id,omitzero" json:"permission_id,omitzero"`
+	UserID       *primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	GroupID      *primitive.ObjectID `bson:"group_id,omitempty" json:"group_id,omitempty"`
+	ObjectType   string              `bson:"object_type,omitzero" json:"object_type,omitzero"`
+	ObjectID     primitive.ObjectID  `bson:"object_id,omitzero" json:"object_id,omitzero"`
+	CreatedAt    time.Time           `bson:"created_at,omitempty"`
+}
+
+// ObjectPermissionPost model info
+// @Description either user_id or group_id receives the permission on the object
+type ObjectPermissionPost struct {
+	PermissionID string `json:"permission_id" validate:"required"`
+	UserID       string `json:"user_id" validate:"required_without=GroupID,excluded_with=GroupID"`
+	GroupID      string `json:"group_id" validate:"required_without=UserID"`
+	ObjectType   string `json:"object_type" validate:"required,oneof=user group permission"`
+	ObjectID     string `json:"object_id" validate:"required"`
+}
+
+// ObjectPermissionGet model info
+// @Description ObjectPermissionGet type information
+type ObjectPermissionGet struct {
+	ID           primitive.ObjectID  `bson:"_id,omitzero" json:"id,omitzero"`
+	PermissionID primitive.ObjectID  `bson:"permission_id,omitzero" json:"permission_id,omitzero"`
+	UserID       *primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	GroupID      *primitive.ObjectID `bson:"group_id,omitempty" json:"group_id,omitempty"`
+	ObjectType   string              `bson:"object_type,omitzero" json:"object_type,omitzero"`
+	ObjectID     primitive.ObjectID  `bson:"object_id,omitzero" json:"object_id,omitzero"`
+	CreatedAt    time.Time           `bson:"created_at,omitempty"`
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OIDCClient Database model info
+// @Description relying party allowed to sign users in through django-auth, public clients have no secret and must use PKCE
+type OIDCClient struct {
+	ID               primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	ClientID         string             `bson:"client_id,omitzero" json:"client_id,omitzero"`
+	ClientSecretHash string             `bson:"client_secret_hash,omitempty" json:"-"`
+	Name             string             `bson:"name,omitzero" json:"name,omitzero"`
+	RedirectURIs     []string           `bson:"redirect_uris,omitzero" json:"redirect_uris,omitzero"`
+	Public           bool               `bson:"public,omitzero" json:"public"`
+	CreatedAt        time.Time          `bson:"created_at,omitempty"`
+	UpdatedAt        time.Time          `bson:"updated_at,omitempty"`
+}
+
+// OIDCClientPost model info
+// @Description OIDCClientPost type information
+type OIDCClientPost struct {
+	Name         string   `json:"name" validate:"required"`
+	RedirectURIs []string `json:"redirect_uris" validate:"required,min=1,dive,url"`
+	Public       bool     `json:"public"`
+}
+
+// OIDCClientGet model info
+// @Description OIDCClientGet type information
+type OIDCClientGet struct {
+	ID           primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	ClientID     string             `bson:"client_id,omitzero" json:"client_id,omitzero"`
+	Name         string             `bson:"name,omitzero" json:"name,omitzero"`
+	RedirectURIs []string           `bson:"redirect_uris,omitzero" json:"redirect_uris,omitzero"`
+	Public       bool               `bson:"public,omitzero" json:"public"`
+	CreatedAt    time.Time          `bson:"created_at,omitempty"`
+	UpdatedAt    time.Time          `bson:"updated_at,omitempty"`
+}
+
+// OIDCClientCreated model info
+// @Description returned once on create, the client_secret is not stored in plain text
+type OIDCClientCreated struct {
+	ClientSecret string `json:"client_secret,omitempty"`
+	OIDCClientGet
+}
+
+// OIDCClientPatch model info
+// @Description OIDCClientPatch type information
+type OIDCClientPatch struct {
+	Name         *string   `json:"name,omitzero"`
+	RedirectURIs *[]string `json:"redirect_uris,omitzero" validate:"omitempty,min=1,dive,url"`
+}
+
+// AuthorizationCode Database model info
+// @Description single use code handed to the client's redirect_uri, only its hash is stored
+type AuthorizationCode struct {
+	ID                  primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	CodeHash            string             `bson:"code_hash,omitzero" json:"-"`
+	ClientID            string             `bson:"client_id,omitzero" json:"client_id,omitzero"`
+	UserID              primitive.ObjectID `bson:"user_id,omitzero" json:"user_id,omitzero"`
+	RedirectURI         string             `bson:"redirect_uri,omitzero" json:"redirect_uri,omitzero"`
+	Scope               string             `bson:"scope,omitzero" json:"scope,omitzero"`
+	Nonce               string             `bson:"nonce,omitempty" json:"nonce,omitzero"`
+	CodeChallenge       string             `bson:"code_challenge,omitempty" json:"-"`
+	CodeChallengeMethod string             `bson:"code_challenge_method,omitempty" json:"-"`
+	AuthTime            time.Time          `bson:"auth_time,omitempty" json:"auth_time,omitzero"`
+	ExpiresAt           time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitzero"`
+	UsedAt              time.Time          `bson:"used_at,omitempty" json:"used_at,omitzero"`
+	CreatedAt           time.Time          `bson:"created_at,omitempty"`
+}
+
+// OIDCAuthorizeRequest model info
+// @Description query parameters of the authorization endpoint
+type OIDCAuthorizeRequest struct {
+	ResponseType        string `query:"response_type" form:"response_type" json:"response_type"`
+	ClientID            string `query:"client_id" form:"client_id" json:"client_id"`
+	RedirectURI         string `query:"redirect_uri" form:"redirect_uri" json:"redirect_uri"`
+	Scope               string `query:"scope" form:"scope" json:"scope"`
+	State               string `query:"state" form:"state" json:"state"`
+	Nonce               string `query:"nonce" form:"nonce" json:"nonce"`
+	CodeChallenge       string `query:"code_challenge" form:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string `query:"code_challenge_method" form:"code_challenge_method" json:"code_challenge_method"`
+}
+
+// OIDCAuthorizeResponse model info
+// @Description where the login page should send the browser next
+type OIDCAuthorizeResponse struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+// OIDCTokenRequest model info
+// @Description form parameters of the token endpoint
+type OIDCTokenRequest struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	CodeVerifier string `form:"code_verifier"`
+}
+
+// OIDCTokenResponse model info
+// @Description OIDCTokenResponse type information
+type OIDCTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	IDToken     string `json:"id_token"`
+	Scope       string `json:"scope"`
+}
+
+// OIDCError model info
+// @Description OAuth 2.0 error response
+type OIDCError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// OIDCDiscovery model info
+// @Description OpenID Provider metadata
+type OIDCDiscovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksURI                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+}
@@ -10,7 +10,15 @@ import (
 type Permission struct {
 	ID       primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
 	Name     string             `bson:"name,omitzero" json:"name,omitzero"`
-	Codename time.Time          `bson:"codename,omitzero" json:"codename,omitzero"`
+	Codename string             `bson:"codename,omitzero" json:"codename,omitzero"`
+	AppLabel string             `bson:"app_label,omitempty" json:"app_label,omitempty"`
+
+	// counts up with every change, sent as the ETag and checked against If-Match
+	Version int64 `bson:"version" json:"version"`
+
+	// set while the document is in the trash, deleted_by is the id of the user who deleted it
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy string     `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
 
 	CreatedAt time.Time `bson:"created_at,omitempty"`
 	UpdatedAt time.Time `bson:"updated_at,omitempty"`
@@ -19,15 +27,25 @@ type Permission struct {
 // PermissionPost model info
 // @Description PermissionPost type information
 type PermissionPost struct {
-	Name string `bson:"name,omitzero" json:"name,omitzero"`
+	Name     string `bson:"name,omitzero" json:"name,omitzero" validate:"required"`
+	Codename string `bson:"codename,omitzero" json:"codename,omitzero" validate:"required"`
+	AppLabel string `bson:"app_label,omitempty" json:"app_label,omitempty"`
 }
 
 // PermissionGet model info
 // @Description PermissionGet type information
 type PermissionGet struct {
-	ID primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	ID       primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	Name     string             `bson:"name,omitzero" json:"name,omitzero"`
+	Codename string             `bson:"codename,omitzero" json:"codename,omitzero"`
+	AppLabel string             `bson:"app_label,omitempty" json:"app_label,omitempty"`
 
-	Codename time.Time `bson:"codename,omitzero" json:"codename,omitzero"`
+	// counts up with every change, sent as the ETag and checked against If-Match
+	Version int64 `bson:"version" json:"version"`
+
+	// set while the document is in the trash, deleted_by is the id of the user who deleted it
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy string     `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
 
 	CreatedAt time.Time `bson:"created_at,omitempty"`
 	UpdatedAt time.Time `bson:"updated_at,omitempty"`
@@ -36,11 +54,45 @@ type PermissionGet struct {
 // PermissionPut model info
 // @Description PermissionPut type information
 type PermissionPut struct {
-	Name *string `bson:"name,omitzero" json:"name,omitzero"`
+	Name     *string `bson:"name,omitzero" json:"name,omitzero"`
+	Codename *string `bson:"codename,omitzero" json:"codename,omitzero" validate:"omitempty,min=1"`
+	AppLabel *string `bson:"app_label,omitempty" json:"app_label,omitempty"`
 }
 
 // PermissionPatch model info
 // @Description PermissionPatch type information
 type PermissionPatch struct {
-	Name *string `bson:"name,omitzero" json:"name,omitzero"`
+	Name     *string `bson:"name,omitzero" json:"name,omitzero"`
+	Codename *string `bson:"codename,omitzero" json:"codename,omitzero" validate:"omitempty,min=1"`
+	AppLabel *string `bson:"app_label,omitempty" json:"app_label,omitempty"`
+}
+
+// sources a user gets a permission from
+const (
+	PermissionSourceDirect = "direct"
+	PermissionSourceGroup  = "group"
+)
+
+// PermissionSource model info
+// @Description one way the user holds the permission, group_id and group_name are set for group grants
+type PermissionSource struct {
+	Type      string              `json:"type"`
+	GroupID   *primitive.ObjectID `json:"group_id,omitempty"`
+	GroupName string              `json:"group_name,omitempty"`
+}
+
+// EffectivePermission model info
+// @Description permission the user holds and every source granting it
+type EffectivePermission struct {
+	PermissionGet
+	Sources []PermissionSource `json:"sources"`
+}
+
+// EffectivePermissions model info
+// @Description union of the direct and group permissions of a user, superusers hold every permission regardless
+type EffectivePermissions struct {
+	UserID      primitive.ObjectID    `json:"user_id"`
+	IsActive    bool                  `json:"is_active"`
+	IsSuperuser bool                  `json:"is_superuser"`
+	Permissions []EffectivePermission `json:"permissions"`
 }
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PersonalAccessToken Database model info
+// @Description long lived token for scripts, only the hash of the token is stored
+type PersonalAccessToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	UserID     primitive.ObjectID `bson:"user_id,omitzero" json:"user_id,omitzero"`
+	Name       string             `bson:"name,omitzero" json:"name,omitzero"`
+	Prefix     string             `bson:"prefix,omitzero" json:"prefix,omitzero"`
+	TokenHash  string             `bson:"token_hash,omitzero" json:"-"`
+	Scopes     []string           `bson:"scopes,omitempty" json:"scopes,omitzero"`
+	ExpiresAt  time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitzero"`
+	LastUsedAt time.Time          `bson:"last_used_at,omitempty" json:"last_used_at,omitzero"`
+	RevokedAt  time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitzero"`
+
+	CreatedAt time.Time `bson:"created_at,omitempty"`
+}
+
+// PersonalAccessTokenPost model info
+// @Description PersonalAccessTokenPost type information
+type PersonalAccessTokenPost struct {
+	Name      string     `json:"name" validate:"required"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at,omitzero"`
+}
+
+// PersonalAccessTokenGet model info
+// @Description PersonalAccessTokenGet type information
+type PersonalAccessTokenGet struct {
+	ID         primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	UserID     primitive.ObjectID `bson:"user_id,omitzero" json:"user_id,omitzero"`
+	Name       string             `bson:"name,omitzero" json:"name,omitzero"`
+	Prefix     string             `bson:"prefix,omitzero" json:"prefix,omitzero"`
+	Scopes     []string           `bson:"scopes,omitempty" json:"scopes,omitzero"`
+	ExpiresAt  time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitzero"`
+	LastUsedAt time.Time          `bson:"last_used_at,omitempty" json:"last_used_at,omitzero"`
+	RevokedAt  time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitzero"`
+
+	CreatedAt time.Time `bson:"created_at,omitempty"`
+}
+
+// PersonalAccessTokenCreated model info
+// @Description the raw token is only returned once, right after creation
+type PersonalAccessTokenCreated struct {
+	Token string `json:"token"`
+	PersonalAccessTokenGet
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshToken Database model info
+// @Description every refresh token issued, tokens from one login share a family
+type RefreshToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	JTI        string             `bson:"jti,omitzero" json:"jti,omitzero"`
+	FamilyID   string             `bson:"family_id,omitzero" json:"family_id,omitzero"`
+	UserID     primitive.ObjectID `bson:"user_id,omitzero" json:"user_id,omitzero"`
+	ReplacedBy string             `bson:"replaced_by,omitempty" json:"replaced_by,omitzero"`
+	ExpiresAt  time.Time          `bson:"expires_at,omitzero" json:"expires_at,omitzero"`
+	RevokedAt  time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitzero"`
+
+	// the access token issued together with this refresh token
+	AccessJTI       string    `bson:"access_jti,omitzero" json:"access_jti,omitzero"`
+	AccessExpiresAt time.Time `bson:"access_expires_at,omitzero" json:"access_expires_at,omitzero"`
+
+	// the superuser acting as the user when the access token is an impersonation token
+	ImpersonatorID primitive.ObjectID `bson:"impersonator_id,omitzero" json:"impersonator_id,omitzero"`
+
+	CreatedAt time.Time `bson:"created_at,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RevokedToken Database model info
+// @Description denylisted token ids, kept until the token would have expired anyway
+type RevokedToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	JTI       string             `bson:"jti,omitzero" json:"jti,omitzero"`
+	UserID    primitive.ObjectID `bson:"user_id,omitzero" json:"user_id,omitzero"`
+	ExpiresAt time.Time          `bson:"expires_at,omitzero" json:"expires_at,omitzero"`
+
+	CreatedAt time.Time `bson:"created_at,omitempty"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Session Database model info
+// @Description server side state of a browser session, only the hash of the cookie value is stored
+type Session struct {
+	ID         primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	KeyHash    string             `bson:"key_hash,omitzero" json:"-"`
+	UserID     primitive.ObjectID `bson:"user_id,omitzero" json:"user_id,omitzero"`
+	CSRFToken  string             `bson:"csrf_token,omitzero" json:"-"`
+	IP         string             `bson:"ip,omitzero" json:"ip,omitzero"`
+	UserAgent  string             `bson:"user_agent,omitzero" json:"user_agent,omitzero"`
+	LastSeenAt time.Time          `bson:"last_seen_at,omitempty" json:"last_seen_at,omitzero"`
+	ExpiresAt  time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitzero"`
+
+	CreatedAt time.Time `bson:"created_at,omitempty"`
+}
+
+// SessionRevocation Database model info
+// @Description cut off time of the cookie sessions of a user when sessions are kept in the cache
+type SessionRevocation struct {
+	UserID    primitive.ObjectID `bson:"_id,omitzero" json:"user_id,omitzero"`
+	RevokedAt time.Time          `bson:"revoked_at,omitzero" json:"revoked_at,omitzero"`
+	ExpiresAt time.Time          `bson:"expires_at,omitzero" json:"expires_at,omitzero"`
+}
+
+// SessionInfo model info
+// @Description the user of a cookie session and the csrf token to send with unsafe requests
+type SessionInfo struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	CSRFToken string    `json:"csrf_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
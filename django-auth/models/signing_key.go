@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SigningKey Database model info
+// @Description token signing key, state is next, active or retired
+type SigningKey struct {
+	ID          primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	Kid         string             `bson:"kid,omitzero" json:"kid,omitzero"`
+	Algorithm   string             `bson:"algorithm,omitzero" json:"algorithm,omitzero"`
+	State       string             `bson:"state,omitzero" json:"state,omitzero"`
+	PrivateKey  string             `bson:"private_key,omitzero" json:"private_key,omitzero"`
+	ActivatedAt time.Time          `bson:"activated_at,omitempty" json:"activated_at,omitzero"`
+	RetiredAt   time.Time          `bson:"retired_at,omitempty" json:"retired_at,omitzero"`
+	RetireAfter time.Time          `bson:"retire_after,omitempty" json:"retire_after,omitzero"`
+	CreatedAt   time.Time          `bson:"created_at,omitempty" json:"created_at,omitzero"`
+}
+
+// SigningKeyGet model info
+// @Description SigningKeyGet type information, the private key is never returned
+type SigningKeyGet struct {
+	ID          primitive.ObjectID `bson:"_id,omitzero" json:"id,omitzero"`
+	Kid         string             `bson:"kid,omitzero" json:"kid,omitzero"`
+	Algorithm   string             `bson:"algorithm,omitzero" json:"algorithm,omitzero"`
+	State       string             `bson:"state,omitzero" json:"state,omitzero"`
+	ActivatedAt time.Time          `bson:"activated_at,omitempty" json:"activated_at,omitzero"`
+	RetiredAt   time.Time          `bson:"retired_at,omitempty" json:"retired_at,omitzero"`
+	RetireAfter time.Time          `bson:"retire_after,omitempty" json:"retire_after,omitzero"`
+	CreatedAt   time.Time          `bson:"created_at,omitempty" json:"created_at,omitzero"`
+}
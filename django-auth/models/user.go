@@ -21,6 +21,27 @@ type User struct {
 	GroupIDs      []primitive.ObjectID `bson:"group_ids,omitempty"    json:"group_ids,omitzero"`
 	PermissionIDs []primitive.ObjectID `bson:"permission_ids,omitempty" json:"permission_ids,omitempty"`
 
+	// auth backend that created the user, empty for local users
+	Backend string `bson:"backend,omitempty" json:"backend,omitempty"`
+
+	// self registered users stay inactive until the email is verified
+	PendingVerification bool      `bson:"pending_verification,omitempty" json:"pending_verification,omitempty"`
+	EmailVerifiedAt     time.Time `bson:"email_verified_at,omitempty" json:"email_verified_at,omitzero"`
+
+	// TOTP multi factor authentication
+	MFAEnabled       bool     `bson:"mfa_enabled,omitzero" json:"mfa_enabled"`
+	MFASecret        string   `bson:"mfa_secret,omitempty" json:"-"`
+	MFAPendingSecret string   `bson:"mfa_pending_secret,omitempty" json:"-"`
+	MFARecoveryCodes []string `bson:"mfa_recovery_codes,omitempty" json:"-"`
+	MFALastStep      int64    `bson:"mfa_last_step,omitempty" json:"-"`
+
+	// counts up with every change, sent as the ETag and checked against If-Match
+	Version int64 `bson:"version" json:"version"`
+
+	// set while the document is in the trash, deleted_by is the id of the user who deleted it
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy string     `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+
 	CreatedAt time.Time `bson:"created_at,omitempty"`
 	UpdatedAt time.Time `bson:"updated_at,omitempty"`
 }
@@ -28,13 +49,13 @@ type User struct {
 // UserPost model info
 // @Description UserPost type information
 type UserPost struct {
-	Password string `bson:"password,omitzero" json:"password,omitzero"`
+	Password string `bson:"password,omitzero" json:"password,omitzero" validate:"required"`
 
 	IsSuperuser bool   `bson:"is_superuser,omitzero" json:"is_superuser"`
-	Username    string `bson:"username,omitzero" json:"username,omitzero"`
+	Username    string `bson:"username,omitzero" json:"username,omitzero" validate:"required"`
 	FirstName   string `bson:"first_name,omitzero" json:"first_name"`
 	LastName    string `bson:"last_name,omitzero" json:"last_name"`
-	Email       string `bson:"email,omitzero" json:"email,omitzero"`
+	Email       string `bson:"email,omitzero" json:"email,omitzero" validate:"omitempty,email"`
 	IsStaff     bool   `bson:"is_staff,omitzero" json:"is_staff"`
 	IsActive    bool   `bson:"is_active,omitzero" json:"is_active"`
 }
@@ -52,6 +73,18 @@ type UserGet struct {
 	Email       string    `bson:"email,omitzero" json:"email,omitzero"`
 	IsStaff     bool      `bson:"is_staff,omitzero" json:"is_staff"`
 	IsActive    bool      `bson:"is_active,omitzero" json:"is_active"`
+	MFAEnabled  bool      `bson:"mfa_enabled,omitzero" json:"mfa_enabled"`
+	Backend     string    `bson:"backend,omitempty" json:"backend,omitempty"`
+
+	PendingVerification bool      `bson:"pending_verification,omitempty" json:"pending_verification,omitempty"`
+	EmailVerifiedAt     time.Time `bson:"email_verified_at,omitempty" json:"email_verified_at,omitzero"`
+
+	// counts up with every change, sent as the ETag and checked against If-Match
+	Version int64 `bson:"version" json:"version"`
+
+	// set while the document is in the trash, deleted_by is the id of the user who deleted it
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy string     `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
 
 	CreatedAt time.Time `bson:"created_at,omitempty"`
 	UpdatedAt time.Time `bson:"updated_at,omitempty"`
@@ -77,10 +110,10 @@ type UserPatch struct {
 	Password *string `bson:"password,omitzero" json:"password,omitzero"`
 
 	IsSuperuser *bool   `bson:"is_superuser,omitzero" json:"is_superuser"`
-	Username    *string `bson:"username,omitzero" json:"username,omitzero"`
+	Username    *string `bson:"username,omitzero" json:"username,omitzero" validate:"omitnil,min=1"`
 	FirstName   *string `bson:"first_name,omitzero" json:"first_name"`
 	LastName    *string `bson:"last_name,omitzero" json:"last_name"`
-	Email       *string `bson:"email,omitzero" json:"email,omitzero"`
+	Email       *string `bson:"email,omitzero" json:"email,omitzero" validate:"omitnil,omitempty,email"`
 	IsStaff     *bool   `bson:"is_staff,omitzero" json:"is_staff"`
 	IsActive    *bool   `bson:"is_active,omitzero" json:"is_active"`
 }
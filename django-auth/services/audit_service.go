@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var HandlerAuditLogService AuditLogService
+
+var ErrAuditLogNotFound = errors.New("audit log entry not found")
+
+// stored in place of secrets in the diff
+const auditRedacted = "[redacted]"
+
+// fields whose values never reach the audit log, only that they changed
+var auditRedactedFields = []string{"password", "mfa_secret", "mfa_pending_secret", "mfa_recovery_codes"}
+
+// fields changing with every write, left out of the diff
+var auditIgnoredFields = []string{"_id", "updated_at", "version"}
+
+// AuditLogService wraps MongoDB logic for the audit log, entries are only inserted
+type AuditLogService struct {
+	Collection *mongo.Collection
+	Client     *mongo.Client
+	Database   *mongo.Database
+}
+
+// Constructor For Client
+func NewAuditLogService(client *mongo.Client) (*AuditLogService, error) {
+	database := client.Database("django_auth")
+	collection := database.Collection("AuditLogs")
+	HandlerAuditLogService = AuditLogService{
+		Collection: collection,
+		Client:     client,
+		Database:   database,
+	}
+	return &HandlerAuditLogService, nil
+}
+
+// EnsureIndexes creates the indexes of the audit log filters
+func (s *AuditLogService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "entity", Value: 1}, {Key: "entity_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "trace_id", Value: 1}}},
+	})
+	return err
+}
+
+// Utility function for transactions
+func (s *AuditLogService) withTransaction(ctx context.Context, fn func(sc mongo.SessionContext) error) error {
+	session, err := s.Client.StartSession()
+	if err != nil {
+		return fmt.Errorf("start session failed: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	return mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
+		if err := session.StartTransaction(); err != nil {
+			return err
+		}
+		if err := fn(sc); err != nil {
+			session.AbortTransaction(sc)
+			return err
+		}
+		return session.CommitTransaction(sc)
+	})
+}
+
+// ##########################################################
+// ##########  Audit Actor
+// ##########################################################
+
+type auditActorKey struct{}
+
+// AuditActor is who makes the changes of a request and through which route, changes without one
+// are made by the system like the scheduled purge
+type AuditActor struct {
+	UserID         string
+	Username       string
+	ImpersonatorID string
+	RouteName      string
+	TraceID        string
+}
+
+// WithAuditActor returns a context whose changes are recorded with the actor
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// withUserAsActor records the changes made while a user logs in as made by that user,
+// the request carries no claim before the login succeeded
+func withUserAsActor(ctx context.Context, userID, username string) context.Context {
+	actor := auditActorFrom(ctx)
+	if actor.UserID == "" {
+		actor.UserID = userID
+		actor.Username = username
+	}
+	return WithAuditActor(ctx, actor)
+}
+
+func auditActorFrom(ctx context.Context) AuditActor {
+	if actor, ok := ctx.Value(auditActorKey{}).(AuditActor); ok {
+		return actor
+	}
+	return AuditActor{Username: "system"}
+}
+
+// ##########################################################
+// ##########  Audited Writes
+// ##########################################################
+
+// audited runs the write in a transaction together with the audit entry holding the field level diff
+// of the document before and after it, the write must use the session context it is given
+func audited(ctx context.Context, collection *mongo.Collection, entity, action string, id primitive.ObjectID, write func(sc mongo.SessionContext) error) error {
+	return HandlerAuditLogService.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		return auditedInTransaction(sc, collection, entity, action, id, write)
+	})
+}
+
+// auditedInTransaction is audited for a write that is part of a transaction already running in sc,
+// like the owners a delete cascades to
+func auditedInTransaction(sc mongo.SessionContext, collection *mongo.Collection, entity, action string, id primitive.ObjectID, write func(sc mongo.SessionContext) error) error {
+	before, err := auditSnapshot(sc, collection, id)
+	if err != nil {
+		return err
+	}
+	if err := write(sc); err != nil {
+		return err
+	}
+	after, err := auditSnapshot(sc, collection, id)
+	if err != nil {
+		return err
+	}
+
+	changes := auditDiff(before, after)
+	// a sync writing the values already stored is not a change
+	if action == models.AuditActionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	actor := auditActorFrom(sc)
+	entry := models.AuditLog{
+		ID:             primitive.NewObjectID(),
+		ActorID:        actor.UserID,
+		ActorUsername:  actor.Username,
+		ImpersonatorID: actor.ImpersonatorID,
+		RouteName:      actor.RouteName,
+		TraceID:        actor.TraceID,
+		Action:         action,
+		Entity:         entity,
+		EntityID:       id.Hex(),
+		Changes:        changes,
+		CreatedAt:      time.Now(),
+	}
+	if _, err := HandlerAuditLogService.Collection.InsertOne(sc, entry); err != nil {
+		return fmt.Errorf("audit log insert failed: %w", err)
+	}
+	return nil
+}
+
+// the document as stored, nil once it does not exist
+func auditSnapshot(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID) (bson.M, error) {
+	var document bson.M
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&document)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s for the audit log: %w", collection.Name(), err)
+	}
+	return document, nil
+}
+
+// changed fields in a stable order, secrets keep only the fact that they changed
+func auditDiff(before, after bson.M) []models.AuditChange {
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, found := before[field]; !found {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	changes := []models.AuditChange{}
+	for _, field := range fields {
+		if slices.Contains(auditIgnoredFields, field) {
+			continue
+		}
+		oldValue, newValue := before[field], after[field]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		change := models.AuditChange{Field: field, Before: oldValue, After: newValue}
+		if slices.Contains(auditRedactedFields, field) {
+			if oldValue != nil {
+				change.Before = auditRedacted
+			}
+			if newValue != nil {
+				change.After = auditRedacted
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// ##########################################################
+// ##########  Audit Log Queries
+// ##########################################################
+
+// GetOne fetches an audit entry by ID
+func (s *AuditLogService) GetOne(ctx context.Context, id string) (*models.AuditLog, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrAuditLogNotFound
+	}
+
+	var entry models.AuditLog
+	err = s.Collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrAuditLogNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Get returns audit entries with pagination, newest first. filters hold exact values for actor_id, action,
+// entity, entity_id, route_name and trace_id, from and to limit created_at.
+func (s *AuditLogService) Get(ctx context.Context, pagination models.Pagination, filters map[string]string, from, to time.Time) ([]models.AuditLog, uint, error) {
+	filter := bson.M{}
+	for field, value := range filters {
+		if value != "" {
+			filter[field] = value
+		}
+	}
+	createdAt := bson.M{}
+	if !from.IsZero() {
+		createdAt["$gte"] = from
+	}
+	if !to.IsZero() {
+		createdAt["$lte"] = to
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+
+	//pagination logic
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64(pagination.Page * pagination.Size)).
+		SetLimit(int64(pagination.Size))
+
+	totalCount, _ := s.Collection.CountDocuments(ctx, filter)
+
+	cursor, err := s.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, uint(totalCount), err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.AuditLog
+	for cursor.Next(ctx) {
+		var entry models.AuditLog
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, uint(totalCount), err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, uint(totalCount), nil
+}
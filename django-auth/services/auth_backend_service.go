@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuthBackends are tried in order on login, set from AUTH_BACKENDS by InitServices
+var AuthBackends []AuthBackend
+
+// AuthBackend checks credentials against one source of users, like an entry of Django's AUTHENTICATION_BACKENDS.
+// ErrInvalidCredentials hands the login over to the next backend, ErrInactiveUser stops it.
+type AuthBackend interface {
+	Name() string
+	Authenticate(ctx context.Context, username, password string) (*models.User, error)
+}
+
+// NewAuthBackends builds the backends listed in AUTH_BACKENDS (local, ldap) in the order they are tried
+func NewAuthBackends() ([]AuthBackend, error) {
+	names := strings.Split(configs.AppConfig.GetOrDefault("AUTH_BACKENDS", LocalAuthBackendName), ",")
+
+	backends := make([]AuthBackend, 0, len(names))
+	for _, name := range names {
+		switch strings.TrimSpace(name) {
+		case LocalAuthBackendName:
+			backends = append(backends, &LocalAuthBackend{Users: HandlerUserService.Collection})
+		case LDAPAuthBackendName:
+			backend, err := NewLDAPAuthBackend(HandlerUserService.Collection, HandlerGroupService.Collection)
+			if err != nil {
+				return nil, err
+			}
+			backends = append(backends, backend)
+		default:
+			return nil, fmt.Errorf("unknown auth backend: %s", name)
+		}
+	}
+	if len(backends) == 0 {
+		return nil, errors.New("no auth backend configured")
+	}
+
+	AuthBackends = backends
+	return backends, nil
+}
+
+// ##########################################################
+// ##########  Local Backend
+// ##########################################################
+
+const LocalAuthBackendName = "local"
+
+// LocalAuthBackend checks the password hash stored on the user
+type LocalAuthBackend struct {
+	Users *mongo.Collection
+}
+
+func (b *LocalAuthBackend) Name() string {
+	return LocalAuthBackendName
+}
+
+// Authenticate verifies the password and upgrades hashes made with an old algorithm or iteration count
+func (b *LocalAuthBackend) Authenticate(ctx context.Context, username, password string) (*models.User, error) {
+	var user models.User
+	// the username index ignores case, so does the login
+	err := b.Users.FindOne(ctx, notDeleted(bson.M{"username": username}),
+		options.FindOne().SetCollation(caseInsensitiveCollation)).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// hash anyway so unknown usernames take as long as wrong passwords
+			models.HashFunc(password)
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	// users of other backends have no local password to check
+	if user.Backend != "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	valid, mustUpdate := utils.CheckPassword(password, user.Password)
+	if !valid {
+		return nil, ErrInvalidCredentials
+	}
+
+	if mustUpdate && user.IsActive {
+		if hashedPassword, err := models.HashFunc(password); err == nil {
+			err := audited(withUserAsActor(ctx, user.ID.Hex(), user.Username), b.Users, models.ObjectTypeUser, models.AuditActionUpdate, user.ID, func(sc mongo.SessionContext) error {
+				_, err := b.Users.UpdateOne(sc, bson.M{"_id": user.ID}, bson.M{
+					"$set": bson.M{"password": hashedPassword},
+				})
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("update password hash failed: %w", err)
+			}
+			user.Password = hashedPassword
+		}
+	}
+
+	return &user, nil
+}
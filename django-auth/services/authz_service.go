@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/bushubdegefu/m-playground/observe"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// reasons given with each authorization decision
+const (
+	AuthzReasonSuperuser        = "superuser"
+	AuthzReasonGranted          = "granted"
+	AuthzReasonGrantedOnObject  = "granted_on_object"
+	AuthzReasonNotGranted       = "not_granted"
+	AuthzReasonInactiveUser     = "inactive_user"
+	AuthzReasonNotInTokenScopes = "not_in_token_scopes"
+)
+
+// AuthenticatedRouteNames are the route names that only need a valid token, not a specific permission,
+// they are never permissions so personal access tokens can not be scoped to them
+var AuthenticatedRouteNames = map[string]bool{
+	"django_auth_logout":           true,
+	"django_auth_manage_own_token": true,
+	"django_auth_manage_own_mfa":   true,
+	"django_auth_session":          true,
+}
+
+// ##########################################################
+// ##########  Authorization Check Services
+// ##########################################################
+
+// AuthzSubject is who an authorization check is made for, Scopes limits personal access tokens
+// and Impersonated drops the superuser bypass
+type AuthzSubject struct {
+	UserID       string
+	Scopes       []string
+	Impersonated bool
+}
+
+// cached permission set of a user
+type effectivePermissionSet struct {
+	Active      bool
+	Superuser   bool
+	Permissions []string
+}
+
+// bumped whenever users, groups or permissions change, sets cached under an older generation are never read again
+var effectivePermissionsGeneration atomic.Int64
+
+// invalidateEffectivePermissions drops every cached permission set of this instance,
+// other instances pick changes up once AUTHZ_CACHE_SECONDS run out. Call it once the change is committed,
+// a check reading the old documents before the commit would otherwise cache them under the new generation
+func invalidateEffectivePermissions() {
+	effectivePermissionsGeneration.Add(1)
+}
+
+// AUTHZ_CACHE_SECONDS, 60 by default
+func authzCacheTTL() time.Duration {
+	seconds, err := strconv.Atoi(configs.AppConfig.GetOrDefault("AUTHZ_CACHE_SECONDS", "60"))
+	if err != nil || seconds <= 0 {
+		seconds = 60
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// cachedEffectivePermissions returns the permission set of the user and whether it came from the cache
+func (s *UserService) cachedEffectivePermissions(ctx context.Context, userID string) (*effectivePermissionSet, bool, error) {
+	if _, err := primitive.ObjectIDFromHex(userID); err != nil {
+		return nil, false, ErrUserNotFound
+	}
+
+	cacheKey := fmt.Sprintf("effective_permissions:%d:%s", effectivePermissionsGeneration.Load(), userID)
+	if cached, found := AppCacheService.Get(cacheKey); found {
+		return cached.(*effectivePermissionSet), true, nil
+	}
+
+	set := &effectivePermissionSet{Active: true}
+	permissions, err := s.GetEffectivePermissions(ctx, userID)
+	switch {
+	case errors.Is(err, ErrInactiveUser):
+		set.Active = false
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return nil, false, ErrUserNotFound
+	case err != nil:
+		return nil, false, err
+	case slices.Contains(permissions, "superuser"):
+		set.Superuser = true
+	default:
+		set.Permissions = permissions
+	}
+
+	AppCacheService.SetWithTTL(cacheKey, set, authzCacheTTL())
+	return set, false, nil
+}
+
+// CheckAuthorization decides every check for the subject, global permissions come from the cached
+// permission set and checks with an object id fall back to object level grants
+func (s *UserService) CheckAuthorization(ctx context.Context, subject AuthzSubject, checks []models.AuthzCheckItem) ([]models.AuthzDecision, error) {
+	start := time.Now()
+
+	set, cached, err := s.cachedEffectivePermissions(ctx, subject.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	decisions := make([]models.AuthzDecision, 0, len(checks))
+	for _, check := range checks {
+		decision := models.AuthzDecision{Codename: check.Codename, ObjectID: check.ObjectID}
+		switch {
+		case !set.Active:
+			decision.Reason = AuthzReasonInactiveUser
+		case subject.Scopes != nil && !slices.Contains(subject.Scopes, check.Codename):
+			decision.Reason = AuthzReasonNotInTokenScopes
+		case set.Superuser && !subject.Impersonated:
+			decision.Allowed, decision.Reason = true, AuthzReasonSuperuser
+		case slices.Contains(set.Permissions, check.Codename):
+			decision.Allowed, decision.Reason = true, AuthzReasonGranted
+		case check.ObjectID != "":
+			granted, err := HandlerObjectPermissionService.HasObjectGrant(ctx, subject.UserID, check.Codename, check.ObjectID)
+			if err != nil {
+				return nil, err
+			}
+			decision.Allowed, decision.Reason = granted, AuthzReasonNotGranted
+			if granted {
+				decision.Reason = AuthzReasonGrantedOnObject
+			}
+		default:
+			decision.Reason = AuthzReasonNotGranted
+		}
+		decisions = append(decisions, decision)
+
+		result := "denied"
+		if decision.Allowed {
+			result = "allowed"
+		}
+		observe.AuthzDecisions.WithLabelValues(result, decision.Reason, configs.AppConfig.Get("APP_NAME")).Inc()
+	}
+
+	cache := "miss"
+	if cached {
+		cache = "hit"
+	}
+	observe.AuthzCheckDuration.WithLabelValues(cache, configs.AppConfig.Get("APP_NAME")).Observe(time.Since(start).Seconds())
+
+	return decisions, nil
+}
+
+// AuthzSubjectFromToken resolves an access token or personal access token to the subject it acts for
+func AuthzSubjectFromToken(ctx context.Context, token string) (*AuthzSubject, error) {
+	if strings.HasPrefix(token, PersonalAccessTokenPrefix) {
+		accessToken, err := HandlerPersonalAccessTokenService.Authenticate(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		// a token without scopes may do nothing, not everything
+		scopes := append([]string{}, accessToken.Scopes...)
+		return &AuthzSubject{UserID: accessToken.UserID.Hex(), Scopes: scopes}, nil
+	}
+
+	claim, err := utils.ParseJWTToken(token, utils.AccessTokenType)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := HandlerRevokedTokenService.IsRevoked(ctx, claim.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, errors.New("token has been revoked")
+	}
+	return &AuthzSubject{UserID: claim.UserID, Impersonated: claim.IsImpersonated()}, nil
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrVersionMismatch is returned when a user, group or permission changed since the version the client read
+var ErrVersionMismatch = errors.New("the document was changed since it was read")
+
+// every change made for a client counts up the version, references pulled while cleaning up
+// after a deleted document do not count as a change of the documents holding them
+var incrementVersion = bson.M{"version": 1}
+
+// matchVersion adds the expected version to the write filter so a stale write matches nothing,
+// without one the write is made whatever the version. Documents stored before versions were kept are version 0
+func matchVersion(filter bson.M, expectedVersion *int64) bson.M {
+	if expectedVersion == nil {
+		return filter
+	}
+	if *expectedVersion == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	} else {
+		filter["version"] = *expectedVersion
+	}
+	return filter
+}
+
+// missingOrStale tells a write that matched nothing because of a stale version from one whose document is gone
+func missingOrStale(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID, expectedVersion *int64, missing error) error {
+	if expectedVersion == nil {
+		return missing
+	}
+	found, err := documentExists(ctx, collection, id)
+	if err != nil {
+		return err
+	}
+	if found {
+		return ErrVersionMismatch
+	}
+	return missing
+}
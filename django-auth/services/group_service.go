@@ -35,40 +35,34 @@ func NewGroupService(client *mongo.Client) (*GroupService, error) {
 	return &HandlerGroupService, nil
 }
 
-// Utility function for transactions
-func (s *GroupService) withTransaction(ctx context.Context, fn func(sc mongo.SessionContext) error) error {
-	session, err := s.Client.StartSession()
-	if err != nil {
-		return fmt.Errorf("start session failed: %w", err)
-	}
-	defer session.EndSession(ctx)
-
-	return mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
-		if err := session.StartTransaction(); err != nil {
-			return err
-		}
-		if err := fn(sc); err != nil {
-			session.AbortTransaction(sc)
-			return err
-		}
-		return session.CommitTransaction(sc)
+// EnsureIndexes makes group names unique
+func (s *GroupService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}, {Key: "deleted_at", Value: 1}},
+		Options: options.Index().SetUnique(true),
 	})
+	return err
 }
 
 // Create inserts a new group
 func (s *GroupService) Create(ctx context.Context, posted_group *models.GroupPost) (*models.GroupGet, error) {
 	var createdGroup = new(models.GroupGet)
 
-	err := s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+	groupID := primitive.NewObjectID()
+	err := audited(ctx, s.Collection, models.ObjectTypeGroup, models.AuditActionCreate, groupID, func(sc mongo.SessionContext) error {
 
 		group := models.Group{
-			ID:        primitive.NewObjectID(),
+			ID:        groupID,
 			Name:      posted_group.Name,
+			Version:   1,
 			CreatedAt: time.Now(),
 		}
 
-		_, err := s.Collection.InsertOne(ctx, group)
+		_, err := s.Collection.InsertOne(sc, group)
 		if err != nil {
+			if conflict := uniqueConflict(err); conflict != nil {
+				return conflict
+			}
 			return fmt.Errorf("insert failed: %w", err)
 		}
 
@@ -98,7 +92,7 @@ func (s *GroupService) GetOne(ctx context.Context, id string) (*models.GroupGet,
 	}
 
 	var group models.GroupGet
-	err = s.Collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&group)
+	err = s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&group)
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +107,7 @@ func (s *GroupService) GetOne(ctx context.Context, id string) (*models.GroupGet,
 func (s *GroupService) Get(ctx context.Context, pagination models.Pagination, searchFields []string, searchTerm []string) ([]models.GroupGet, uint, error) {
 
 	// Build search query if any
-	filter := bson.M{}
+	filter := notDeleted(bson.M{})
 	if len(searchTerm) > 0 && len(searchFields) > 0 && len(searchFields) >= len(searchTerm) {
 		var orConditions []bson.M
 		for index, term := range searchTerm {
@@ -152,16 +146,16 @@ func (s *GroupService) Get(ctx context.Context, pagination models.Pagination, se
 }
 
 // Update modifies a Groups by ID
-func (s *GroupService) Update(ctx context.Context, patch_group *models.GroupPatch, id string) (*models.GroupGet, error) {
+func (s *GroupService) Update(ctx context.Context, patch_group *models.GroupPatch, id string, expectedVersion *int64) (*models.GroupGet, error) {
 	// update User
-	var updatedGroup *models.GroupGet
+	var updatedGroup = new(models.GroupGet)
 
 	group_id, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return &models.GroupGet{}, fmt.Errorf("invalid ID: %w", err)
 	}
 
-	err = s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+	err = audited(ctx, s.Collection, models.ObjectTypeGroup, models.AuditActionUpdate, group_id, func(sc mongo.SessionContext) error {
 		updateFields := bson.M{}
 		if patch_group.Name != nil {
 			updateFields["name"] = *patch_group.Name
@@ -169,92 +163,177 @@ func (s *GroupService) Update(ctx context.Context, patch_group *models.GroupPatc
 		updateFields["updated_at"] = time.Now()
 
 		// filter to use to update value by
-		filterGroup := bson.M{"_id": group_id}
-		updateGroup := bson.M{"$set": updateFields}
-		// Update the document by ID
-		_, err := s.Collection.UpdateOne(ctx, filterGroup, updateGroup)
+		filterGroup := matchVersion(notDeleted(bson.M{"_id": group_id}), expectedVersion)
+		updateGroup := bson.M{"$set": updateFields, "$inc": incrementVersion}
+		// Update the document by ID, returning it as stored after the update
+		err := s.Collection.FindOneAndUpdate(sc, filterGroup, updateGroup,
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(updatedGroup)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return missingOrStale(sc, s.Collection, group_id, expectedVersion, ErrGroupNotFound)
+		}
 		if err != nil {
-			return fmt.Errorf("insert failed: %w", err)
+			if conflict := uniqueConflict(err); conflict != nil {
+				return conflict
+			}
+			return fmt.Errorf("update failed: %w", err)
 		}
 
-		// Removing Cache if update sucess
-		cacheKey := "group:" + id
-		AppCacheService.Delete(cacheKey)
-
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	copier.Copy(&updatedGroup, patch_group)
-	return updatedGroup, err
+	// Removing Cache once the update is committed, a read before the commit would cache the old version again
+	AppCacheService.Delete("group:" + id)
+
+	return updatedGroup, nil
 }
 
-// Delete removes a group by ID
-func (s *GroupService) Delete(ctx context.Context, id string) error {
+// Delete moves a group to the trash, or removes it for good when SOFT_DELETE_ENABLED is false
+func (s *GroupService) Delete(ctx context.Context, id string, deletedBy string, expectedVersion *int64) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %w", err)
+	}
 
-	err := s.withTransaction(ctx, func(sc mongo.SessionContext) error {
-		objID, err := primitive.ObjectIDFromHex(id)
-		if err != nil {
-			return fmt.Errorf("invalid ID: %w", err)
-		}
+	if SoftDeleteEnabled() {
+		err = audited(ctx, s.Collection, models.ObjectTypeGroup, models.AuditActionDelete, objID, func(sc mongo.SessionContext) error {
+			return softDelete(sc, s.Collection, objID, deletedBy, expectedVersion, ErrGroupNotFound)
+		})
+	} else {
+		err = s.hardDelete(ctx, objID, time.Time{}, expectedVersion)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Removing Cache if delete sucess
+	cacheKey := "group:" + id
+	AppCacheService.Delete(cacheKey)
+	invalidateEffectivePermissions()
+
+	return nil
+}
 
-		result, err := s.Collection.DeleteOne(ctx, bson.M{"_id": objID})
+// hardDelete removes a group for good, with trashedBefore set only when it went to the trash before then
+// and expectedVersion set only when it must still be that version
+func (s *GroupService) hardDelete(ctx context.Context, objID primitive.ObjectID, trashedBefore time.Time, expectedVersion *int64) error {
+	filter := matchVersion(bson.M{"_id": objID}, expectedVersion)
+	if !trashedBefore.IsZero() {
+		filter["deleted_at"] = bson.M{"$lt": trashedBefore}
+	}
+
+	// a delete without the trash or the scheduled purge of the trash
+	action := models.AuditActionDelete
+	if !trashedBefore.IsZero() {
+		action = models.AuditActionPurge
+	}
+
+	var members []primitive.ObjectID
+	err := audited(ctx, s.Collection, models.ObjectTypeGroup, action, objID, func(sc mongo.SessionContext) error {
+		result, err := s.Collection.DeleteOne(sc, filter)
 		if err != nil {
 			return err
 		}
 
 		if result.DeletedCount == 0 {
-			return errors.New("no document deleted")
+			return missingOrStale(sc, s.Collection, objID, expectedVersion, ErrGroupNotFound)
 		}
 
-		// Removing Cache if delete sucess
-		cacheKey := "group:" + id
-		AppCacheService.Delete(cacheKey)
+		// members keep no reference to the deleted group
+		members, err = relation{owners: HandlerUserService.Collection, entity: models.ObjectTypeUser, field: "group_ids"}.detach(sc, objID)
+		if err != nil {
+			return err
+		}
+		if err := HandlerObjectPermissionService.deleteReferencing(sc, "group_id", models.ObjectTypeGroup, objID); err != nil {
+			return err
+		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	AppCacheService.Delete("group:" + objID.Hex())
+	for _, member := range members {
+		AppCacheService.Delete("user:" + member.Hex())
+	}
+	invalidateEffectivePermissions()
+	return nil
 }
 
-// ##########################################################
-// ##########  Relationship  Services to Permission
-// ##########################################################
+// Restore takes a group out of the trash
+func (s *GroupService) Restore(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrGroupNotFound
+	}
 
-func (s *GroupService) AddGroupToPermission(ctx context.Context, groupID, permissionID string) error {
-	group_id, err := primitive.ObjectIDFromHex(groupID)
-	permission_id, err := primitive.ObjectIDFromHex(permissionID)
+	err = audited(ctx, s.Collection, models.ObjectTypeGroup, models.AuditActionRestore, objID, func(sc mongo.SessionContext) error {
+		return restore(sc, s.Collection, objID, ErrGroupNotFound)
+	})
 	if err != nil {
 		return err
 	}
 
-	_, err = s.Collection.UpdateOne(ctx, bson.M{"_id": group_id}, bson.M{
-		"$addToSet": bson.M{"permission_ids": permission_id}, // Prevents duplicates
-	})
-	return err
+	AppCacheService.Delete("group:" + id)
+	invalidateEffectivePermissions()
+	return nil
 }
 
-func (s *GroupService) RemoveGroupFromPermission(ctx context.Context, groupID, permissionID string) error {
-	group_id, err := primitive.ObjectIDFromHex(groupID)
-	permission_id, err := primitive.ObjectIDFromHex(permissionID)
+// GetTrash returns the groups in the trash with pagination, most recently deleted first
+func (s *GroupService) GetTrash(ctx context.Context, pagination models.Pagination) ([]models.GroupGet, uint, error) {
+	filter := inTrash()
+
+	//pagination logic
+	opts := options.Find().
+		SetSort(bson.D{{Key: "deleted_at", Value: -1}}).
+		SetSkip(int64(pagination.Page * pagination.Size)).
+		SetLimit(int64(pagination.Size))
+
+	totalCount, _ := s.Collection.CountDocuments(ctx, filter)
+
+	cursor, err := s.Collection.Find(ctx, filter, opts)
 	if err != nil {
-		return err
+		return nil, uint(totalCount), err
 	}
+	defer cursor.Close(ctx)
 
-	_, err = s.Collection.UpdateOne(ctx, bson.M{"_id": group_id}, bson.M{
-		"$pull": bson.M{"permission_ids": permission_id},
-	})
-	return err
+	var groups []models.GroupGet
+	for cursor.Next(ctx) {
+		var item models.GroupGet
+		if err := cursor.Decode(&item); err != nil {
+			return nil, uint(totalCount), err
+		}
+		groups = append(groups, item)
+	}
+
+	return groups, uint(totalCount), nil
+}
+
+// ##########################################################
+// ##########  Relationship  Services to Permission
+// ##########################################################
+
+func (s *GroupService) AddGroupToPermission(ctx context.Context, groupID, permissionID string) error {
+	return relation{s.Collection, models.ObjectTypeGroup, ErrGroupNotFound, "permission_ids", HandlerPermissionService.Collection, ErrPermissionNotFound}.add(ctx, groupID, permissionID)
+}
+
+func (s *GroupService) RemoveGroupFromPermission(ctx context.Context, groupID, permissionID string) error {
+	return relation{s.Collection, models.ObjectTypeGroup, ErrGroupNotFound, "permission_ids", HandlerPermissionService.Collection, ErrPermissionNotFound}.remove(ctx, groupID, permissionID)
 }
 
 func (s *GroupService) GetGroupPermissions(ctx context.Context, groupID string, pagination models.Pagination) ([]models.Permission, uint, error) {
 	group_id, _ := primitive.ObjectIDFromHex(groupID)
 	var group models.Group
-	if err := s.Collection.FindOne(ctx, bson.M{"_id": group_id}).Decode(&group); err != nil {
+	if err := s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": group_id})).Decode(&group); err != nil {
 		return nil, 0, err
 	}
 
 	permissionCollection := s.Database.Collection("Permissions")
-	filter := bson.M{"_id": bson.M{"$in": group.PermissionIDs}}
+	filter := notDeleted(bson.M{"_id": bson.M{"$in": group.PermissionIDs}})
 	opts := options.Find().
 		SetSkip(int64(pagination.Page * pagination.Size)).
 		SetLimit(int64(pagination.Size))
@@ -288,12 +367,12 @@ func (s *GroupService) GetAllPermissionsForGroup(ctx context.Context, groupID st
 	}
 
 	var group models.Group
-	if err := s.Collection.FindOne(ctx, bson.M{"_id": group_id}).Decode(&group); err != nil {
+	if err := s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": group_id})).Decode(&group); err != nil {
 		return nil, fmt.Errorf("failed to fetch group: %w", err)
 	}
 
 	permissionCollection := s.Database.Collection("Permissions")
-	filter := bson.M{"_id": bson.M{"$in": group.PermissionIDs}}
+	filter := notDeleted(bson.M{"_id": bson.M{"$in": group.PermissionIDs}})
 
 	cursor, err := permissionCollection.Find(ctx, filter)
 	if err != nil {
@@ -320,13 +399,13 @@ func (s *GroupService) GetAllPermissionsgroupDoesNotHave(ctx context.Context, gr
 	}
 
 	var group models.Group
-	if err := s.Collection.FindOne(ctx, bson.M{"_id": group_id}).Decode(&group); err != nil {
+	if err := s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": group_id})).Decode(&group); err != nil {
 		return nil, fmt.Errorf("failed to fetch group: %w", err)
 	}
 
 	permissionCollection := s.Database.Collection("Permissions")
 
-	filter := bson.M{}
+	filter := notDeleted(bson.M{})
 	if len(group.PermissionIDs) > 0 {
 		filter["_id"] = bson.M{"$nin": group.PermissionIDs}
 	}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrImpersonationForbidden = errors.New("only superusers can impersonate other users")
+	ErrImpersonationChained   = errors.New("an impersonation token can not start another impersonation")
+	ErrImpersonationTarget    = errors.New("this user can not be impersonated")
+	ErrUserNotFound           = errors.New("user not found")
+)
+
+// ##########################################################
+// ##########  Impersonation Services
+// ##########################################################
+
+// ImpersonationClaim builds the claim of a token that lets the superuser act as the user. The claim names the
+// superuser in act, never carries superuser status and can not be used to impersonate anyone else.
+func (s *UserService) ImpersonationClaim(ctx context.Context, actor *utils.UserClaim, userID string) (*utils.UserClaim, error) {
+	if actor.IsImpersonated() {
+		return nil, ErrImpersonationChained
+	}
+
+	// personal access tokens and OIDC tokens are delegated, only a login can impersonate
+	if actor.TokenType != utils.AccessTokenType && actor.TokenType != utils.SessionTokenType {
+		return nil, ErrImpersonationForbidden
+	}
+
+	// checking the stored user, the claim may predate a removed superuser status
+	actorUser, err := s.findUser(ctx, actor.UserID)
+	if err != nil || !actorUser.IsActive || !actorUser.IsSuperuser {
+		return nil, ErrImpersonationForbidden
+	}
+
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	// impersonating a superuser would hand out superuser powers through the back door
+	if !user.IsActive || user.IsSuperuser || user.ID == actorUser.ID {
+		return nil, ErrImpersonationTarget
+	}
+
+	log.Printf("impersonation started: superuser %s (%s) is acting as user %s (%s)",
+		actorUser.ID.Hex(), actorUser.Username, user.ID.Hex(), user.Username)
+
+	return &utils.UserClaim{
+		UserID:   user.ID.Hex(),
+		Username: user.Username,
+		Email:    user.Email,
+		Act: &utils.ActorClaim{
+			UserID:   actorUser.ID.Hex(),
+			Username: actorUser.Username,
+		},
+	}, nil
+}
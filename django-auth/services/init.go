@@ -1,11 +1,18 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/bushubdegefu/m-playground/cache"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/bushubdegefu/m-playground/mailer"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 var AppCacheService *cache.CacheService
+var AppMailer mailer.Mailer
 
 func InitServices(client *mongo.Client) {
 	var err error
@@ -14,8 +21,94 @@ func InitServices(client *mongo.Client) {
 		panic("Unable to initialize cache service")
 	}
 
+	if err := utils.RequireTokenSecret("PASSWORD_RESET_SECRET"); err != nil {
+		panic(fmt.Sprintf("Unable to load password reset secret: %v", err))
+	}
+
+	// verification links are only mailed while sign up is open
+	if RegistrationEnabled() {
+		if err := utils.RequireTokenSecret("EMAIL_VERIFICATION_SECRET"); err != nil {
+			panic(fmt.Sprintf("Unable to load email verification secret: %v", err))
+		}
+	}
+
+	AppMailer, err = mailer.NewMailer()
+	if err != nil {
+		panic(fmt.Sprintf("Unable to initialize mailer: %v", err))
+	}
+
 	// Initialize services here
 	NewUserService(client)
 	NewGroupService(client)
 	NewPermissionService(client)
+	NewObjectPermissionService(client)
+	NewAuditLogService(client)
+	NewRefreshTokenService(client)
+	NewRevokedTokenService(client)
+	NewPersonalAccessTokenService(client)
+	NewLoginAttemptService(client)
+	NewOIDCClientService(client)
+	NewAuthorizationCodeService(client)
+	if _, err := NewSigningKeyService(client); err != nil {
+		panic(fmt.Sprintf("Unable to initialize signing key service: %v", err))
+	}
+	if _, err := NewSessionService(client); err != nil {
+		panic(fmt.Sprintf("Unable to initialize session service: %v", err))
+	}
+	if _, err := NewAuthBackends(); err != nil {
+		panic(fmt.Sprintf("Unable to initialize auth backends: %v", err))
+	}
+
+	// Ensuring indexes before serving requests, existing duplicates must be resolved before the unique ones can be built
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := HandlerUserService.EnsureIndexes(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to create user indexes: %v", err))
+	}
+	if err := HandlerGroupService.EnsureIndexes(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to create group indexes: %v", err))
+	}
+	if err := HandlerRefreshTokenService.EnsureIndexes(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to create refresh token indexes: %v", err))
+	}
+	if err := HandlerRevokedTokenService.EnsureIndexes(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to create revoked token indexes: %v", err))
+	}
+	if err := HandlerPersonalAccessTokenService.EnsureIndexes(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to create personal access token indexes: %v", err))
+	}
+	if err := HandlerLoginAttemptService.EnsureIndexes(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to create login attempt indexes: %v", err))
+	}
+	if err := HandlerOIDCClientService.EnsureIndexes(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to create oidc client indexes: %v", err))
+	}
+	if err := HandlerAuthorizationCodeService.EnsureIndexes(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to create authorization code indexes: %v", err))
+	}
+	if err := HandlerSigningKeyService.EnsureIndexes(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to create signing key indexes: %v", err))
+	}
+	if err := HandlerSessionService.EnsureIndexes(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to create session indexes: %v", err))
+	}
+	if err := HandlerObjectPermissionService.EnsureIndexes(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to create object permission indexes: %v", err))
+	}
+	if err := HandlerPermissionService.EnsureIndexes(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to create permission indexes: %v", err))
+	}
+	if err := HandlerAuditLogService.EnsureIndexes(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to create audit log indexes: %v", err))
+	}
+
+	// permissions must decode before any route is authorized
+	if err := HandlerPermissionService.MigrateCodenames(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to migrate permission codenames: %v", err))
+	}
+
+	// tokens can not be signed or verified before the key ring is loaded
+	if err := HandlerSigningKeyService.Load(ctx); err != nil {
+		panic(fmt.Sprintf("Unable to load signing keys: %v", err))
+	}
 }
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrGroupNotFound      = errors.New("group not found")
+	ErrPermissionNotFound = errors.New("permission not found")
+)
+
+// ##########################################################
+// ##########  Relationship Integrity Services
+// ##########################################################
+
+// relation is an id array on owner documents referencing target documents, like group_ids on users
+type relation struct {
+	owners        *mongo.Collection
+	entity        string
+	ownerMissing  error
+	field         string
+	targets       *mongo.Collection
+	targetMissing error
+}
+
+// add references the target from the owner, both must exist
+func (r relation) add(ctx context.Context, ownerID, targetID string) error {
+	owner_id, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return r.ownerMissing
+	}
+	target_id, err := primitive.ObjectIDFromHex(targetID)
+	if err != nil {
+		return r.targetMissing
+	}
+
+	err = audited(ctx, r.owners, r.entity, models.AuditActionAddRelation, owner_id, func(sc mongo.SessionContext) error {
+		found, err := documentExists(sc, r.targets, target_id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return r.targetMissing
+		}
+
+		result, err := r.owners.UpdateOne(sc, notDeleted(bson.M{"_id": owner_id}), bson.M{
+			"$addToSet": bson.M{r.field: target_id}, // Prevents duplicates
+			"$inc":      incrementVersion,
+		})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return r.ownerMissing
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	AppCacheService.Delete(r.entity + ":" + ownerID)
+	invalidateEffectivePermissions()
+	return nil
+}
+
+// remove drops the reference from the owner, the target may already be gone
+func (r relation) remove(ctx context.Context, ownerID, targetID string) error {
+	owner_id, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return r.ownerMissing
+	}
+	target_id, err := primitive.ObjectIDFromHex(targetID)
+	if err != nil {
+		return r.targetMissing
+	}
+
+	err = audited(ctx, r.owners, r.entity, models.AuditActionRemoveRelation, owner_id, func(sc mongo.SessionContext) error {
+		result, err := r.owners.UpdateOne(sc, notDeleted(bson.M{"_id": owner_id}), bson.M{
+			"$pull": bson.M{r.field: target_id},
+			"$inc":  incrementVersion,
+		})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return r.ownerMissing
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	AppCacheService.Delete(r.entity + ":" + ownerID)
+	invalidateEffectivePermissions()
+	return nil
+}
+
+// detach pulls the deleted target from every owner referencing it with a remove_relation entry per owner.
+// It runs in the transaction of the delete, the detached owners are returned to drop them from the cache after the commit
+func (r relation) detach(sc mongo.SessionContext, target_id primitive.ObjectID) ([]primitive.ObjectID, error) {
+	ownerIDs, err := existingIDs(sc, r.owners, bson.M{r.field: target_id})
+	if err != nil {
+		return nil, err
+	}
+
+	detached := make([]primitive.ObjectID, 0, len(ownerIDs))
+	for owner_id := range ownerIDs {
+		err := auditedInTransaction(sc, r.owners, r.entity, models.AuditActionRemoveRelation, owner_id, func(sc mongo.SessionContext) error {
+			_, err := r.owners.UpdateOne(sc, bson.M{"_id": owner_id}, bson.M{
+				"$pull": bson.M{r.field: target_id},
+				"$inc":  incrementVersion,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove %s from %s: %w", r.field, r.owners.Name(), err)
+		}
+		detached = append(detached, owner_id)
+	}
+	return detached, nil
+}
+
+// documentExists counts documents matching the id in the collection, documents in the trash do not count
+func documentExists(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID) (bool, error) {
+	count, err := collection.CountDocuments(ctx, notDeleted(bson.M{"_id": id}), options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+// ids of the documents matching the filter
+func existingIDs(ctx context.Context, collection *mongo.Collection, filter bson.M) (map[primitive.ObjectID]bool, error) {
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", collection.Name(), err)
+	}
+	var documents []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", collection.Name(), err)
+	}
+
+	ids := make(map[primitive.ObjectID]bool, len(documents))
+	for _, document := range documents {
+		ids[document.ID] = true
+	}
+	return ids, nil
+}
+
+// id arrays on users and groups and the collection they point into
+var referenceArrays = []struct {
+	owners, field, targets string
+}{
+	{"Users", "group_ids", "Groups"},
+	{"Users", "permission_ids", "Permissions"},
+	{"Groups", "permission_ids", "Permissions"},
+}
+
+// a reference found dangling, missing ids all point into targets
+type orphan struct {
+	reference  models.OrphanReference
+	documentID primitive.ObjectID
+	targets    string
+	missing    []primitive.ObjectID
+}
+
+// FindOrphans reports references to users, groups and permissions that no longer exist. With repair the ids
+// are pulled from users and groups and object permissions naming a missing document are deleted.
+func FindOrphans(ctx context.Context, repair bool) (*models.OrphanReport, error) {
+	database := HandlerUserService.Database
+	existing := make(map[string]map[primitive.ObjectID]bool)
+	for _, name := range []string{"Users", "Groups", "Permissions"} {
+		ids, err := existingIDs(ctx, database.Collection(name), bson.M{})
+		if err != nil {
+			return nil, err
+		}
+		existing[name] = ids
+	}
+
+	var orphans []orphan
+	for _, array := range referenceArrays {
+		cursor, err := database.Collection(array.owners).Find(ctx, bson.M{array.field + ".0": bson.M{"$exists": true}},
+			options.Find().SetProjection(bson.M{"ids": "$" + array.field}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", array.owners, err)
+		}
+		var owners []struct {
+			ID  primitive.ObjectID   `bson:"_id"`
+			IDs []primitive.ObjectID `bson:"ids"`
+		}
+		if err := cursor.All(ctx, &owners); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", array.owners, err)
+		}
+
+		for _, owner := range owners {
+			var missing []primitive.ObjectID
+			for _, id := range owner.IDs {
+				if !existing[array.targets][id] {
+					missing = append(missing, id)
+				}
+			}
+			if len(missing) > 0 {
+				orphans = append(orphans, orphan{
+					reference:  models.OrphanReference{Collection: array.owners, DocumentID: owner.ID.Hex(), Field: array.field},
+					documentID: owner.ID,
+					targets:    array.targets,
+					missing:    missing,
+				})
+			}
+		}
+	}
+
+	cursor, err := HandlerObjectPermissionService.Collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch object permissions: %w", err)
+	}
+	var grants []models.ObjectPermission
+	if err := cursor.All(ctx, &grants); err != nil {
+		return nil, fmt.Errorf("failed to decode object permissions: %w", err)
+	}
+	for _, grant := range grants {
+		fields := []struct {
+			field, targets string
+			id             *primitive.ObjectID
+		}{
+			{"permission_id", "Permissions", &grant.PermissionID},
+			{"user_id", "Users", grant.UserID},
+			{"group_id", "Groups", grant.GroupID},
+			{"object_id", objectPermissionCollections[grant.ObjectType], &grant.ObjectID},
+		}
+		for _, field := range fields {
+			if field.id == nil || existing[field.targets][*field.id] {
+				continue
+			}
+			orphans = append(orphans, orphan{
+				reference:  models.OrphanReference{Collection: "ObjectPermissions", DocumentID: grant.ID.Hex(), Field: field.field},
+				documentID: grant.ID,
+				targets:    field.targets,
+				missing:    []primitive.ObjectID{*field.id},
+			})
+		}
+	}
+
+	// documents created while scanning are not orphans, the missing ids are looked up once more
+	candidates := make(map[string][]primitive.ObjectID)
+	for _, o := range orphans {
+		candidates[o.targets] = append(candidates[o.targets], o.missing...)
+	}
+	created := make(map[string]map[primitive.ObjectID]bool)
+	for targets, ids := range candidates {
+		if _, known := existing[targets]; !known {
+			continue
+		}
+		found, err := existingIDs(ctx, database.Collection(targets), bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return nil, err
+		}
+		created[targets] = found
+	}
+
+	report := &models.OrphanReport{References: []models.OrphanReference{}, Repaired: repair}
+	for _, o := range orphans {
+		var missing []primitive.ObjectID
+		for _, id := range o.missing {
+			if !created[o.targets][id] {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		reference := o.reference
+		for _, id := range missing {
+			reference.MissingIDs = append(reference.MissingIDs, id.Hex())
+		}
+		report.References = append(report.References, reference)
+
+		if repair {
+			if err := repairOrphan(ctx, reference.Collection, o.documentID, reference.Field, missing); err != nil {
+				return nil, err
+			}
+		}
+	}
+	report.Total = len(report.References)
+
+	if repair && report.Total > 0 {
+		invalidateEffectivePermissions()
+	}
+	return report, nil
+}
+
+// pulls the missing ids from a user or group, a grant naming a missing document is deleted as a whole
+func repairOrphan(ctx context.Context, collection string, id primitive.ObjectID, field string, missing []primitive.ObjectID) error {
+	var err error
+	if collection == "ObjectPermissions" {
+		grants := HandlerObjectPermissionService.Collection
+		err = audited(ctx, grants, models.AuditEntityObjectPermission, models.AuditActionDelete, id, func(sc mongo.SessionContext) error {
+			_, err := grants.DeleteOne(sc, bson.M{"_id": id})
+			return err
+		})
+	} else {
+		entity := models.ObjectTypeUser
+		if collection == HandlerGroupService.Collection.Name() {
+			entity = models.ObjectTypeGroup
+		}
+		owners := HandlerUserService.Database.Collection(collection)
+		err = audited(ctx, owners, entity, models.AuditActionUpdate, id, func(sc mongo.SessionContext) error {
+			_, err := owners.UpdateOne(sc, bson.M{"_id": id}, bson.M{
+				"$pull": bson.M{field: bson.M{"$in": missing}},
+			})
+			return err
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("repair of %s %s failed: %w", collection, id.Hex(), err)
+	}
+	return nil
+}
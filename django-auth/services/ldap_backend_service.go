@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/go-ldap/ldap/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const LDAPAuthBackendName = "ldap"
+
+// LDAPAuthBackend logs users in with a simple bind as the user, the user is created on first login
+// and the name, email and mapped groups are updated from the directory on every login
+type LDAPAuthBackend struct {
+	Users  *mongo.Collection
+	Groups *mongo.Collection
+
+	// Dial opens the directory connection, tests can point it at an in-process server
+	// for example with ldap.NewConn over one end of a net.Pipe
+	Dial func(ctx context.Context) (*ldap.Conn, error)
+
+	// LDAP_USER_DN_TEMPLATE, the escaped username replaces %s, e.g. uid=%s,ou=people,dc=example,dc=com
+	UserDNTemplate string
+
+	// LDAP_ATTR_FIRST_NAME, LDAP_ATTR_LAST_NAME and LDAP_ATTR_EMAIL
+	FirstNameAttribute string
+	LastNameAttribute  string
+	EmailAttribute     string
+
+	// groups come from LDAP_GROUP_ATTRIBUTE on the user entry, or with LDAP_GROUP_SEARCH_BASE set
+	// from the entries matching LDAP_GROUP_SEARCH_FILTER where %s is the user DN
+	GroupAttribute    string
+	GroupSearchBase   string
+	GroupSearchFilter string
+
+	// LDAP_GROUP_MAP, json object of group DN to django-auth group name
+	GroupMap map[string]string
+
+	Timeout time.Duration
+}
+
+// NewLDAPAuthBackend reads the LDAP_* settings, the default dialer connects to LDAP_URL
+func NewLDAPAuthBackend(users *mongo.Collection, groups *mongo.Collection) (*LDAPAuthBackend, error) {
+	serverURL := configs.AppConfig.GetOrDefault("LDAP_URL", "")
+	if serverURL == "" {
+		return nil, errors.New("LDAP_URL is required for the ldap auth backend")
+	}
+
+	template := configs.AppConfig.GetOrDefault("LDAP_USER_DN_TEMPLATE", "")
+	if strings.Count(template, "%s") != 1 {
+		return nil, errors.New("LDAP_USER_DN_TEMPLATE must contain %s exactly once")
+	}
+
+	groupMap := map[string]string{}
+	if raw := configs.AppConfig.GetOrDefault("LDAP_GROUP_MAP", ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &groupMap); err != nil {
+			return nil, fmt.Errorf("LDAP_GROUP_MAP is not a json object: %w", err)
+		}
+	}
+
+	seconds, err := strconv.Atoi(configs.AppConfig.GetOrDefault("LDAP_TIMEOUT_SECONDS", "5"))
+	if err != nil || seconds <= 0 {
+		seconds = 5
+	}
+
+	backend := &LDAPAuthBackend{
+		Users:              users,
+		Groups:             groups,
+		UserDNTemplate:     template,
+		FirstNameAttribute: configs.AppConfig.GetOrDefault("LDAP_ATTR_FIRST_NAME", "givenName"),
+		LastNameAttribute:  configs.AppConfig.GetOrDefault("LDAP_ATTR_LAST_NAME", "sn"),
+		EmailAttribute:     configs.AppConfig.GetOrDefault("LDAP_ATTR_EMAIL", "mail"),
+		GroupAttribute:     configs.AppConfig.GetOrDefault("LDAP_GROUP_ATTRIBUTE", "memberOf"),
+		GroupSearchBase:    configs.AppConfig.GetOrDefault("LDAP_GROUP_SEARCH_BASE", ""),
+		GroupSearchFilter:  configs.AppConfig.GetOrDefault("LDAP_GROUP_SEARCH_FILTER", "(member=%s)"),
+		GroupMap:           groupMap,
+		Timeout:            time.Duration(seconds) * time.Second,
+	}
+
+	startTLS := configs.AppConfig.GetOrDefault("LDAP_START_TLS", "false") == "true"
+	backend.Dial = func(ctx context.Context) (*ldap.Conn, error) {
+		conn, err := ldap.DialURL(serverURL, ldap.DialWithDialer(&net.Dialer{Timeout: backend.Timeout}))
+		if err != nil {
+			return nil, err
+		}
+		if startTLS {
+			parsed, err := url.Parse(serverURL)
+			if err != nil {
+				conn.Close()
+				return nil, err
+			}
+			if err := conn.StartTLS(&tls.Config{ServerName: parsed.Hostname()}); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+		return conn, nil
+	}
+
+	return backend, nil
+}
+
+func (b *LDAPAuthBackend) Name() string {
+	return LDAPAuthBackendName
+}
+
+// Authenticate binds as the user and syncs the directory entry into the user record
+func (b *LDAPAuthBackend) Authenticate(ctx context.Context, username, password string) (*models.User, error) {
+	// an empty password is an unauthenticated bind, which many servers accept
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := b.Dial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ldap connect failed: %w", err)
+	}
+	defer conn.Close()
+	conn.SetTimeout(b.Timeout)
+
+	userDN := fmt.Sprintf(b.UserDNTemplate, ldap.EscapeDN(username))
+	if err := conn.Bind(userDN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("ldap bind failed: %w", err)
+	}
+
+	// reading the entry with the user's own bind
+	result, err := conn.Search(ldap.NewSearchRequest(
+		userDN, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 1, int(b.Timeout.Seconds()), false,
+		"(objectClass=*)",
+		[]string{b.FirstNameAttribute, b.LastNameAttribute, b.EmailAttribute, b.GroupAttribute},
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("ldap user lookup failed: %w", err)
+	}
+	if len(result.Entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	entry := result.Entries[0]
+
+	groupDNs := entry.GetEqualFoldAttributeValues(b.GroupAttribute)
+	if b.GroupSearchBase != "" {
+		if groupDNs, err = b.searchGroups(conn, userDN); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.syncUser(ctx, username, entry, groupDNs)
+}
+
+// DNs of the groups whose members include the user
+func (b *LDAPAuthBackend) searchGroups(conn *ldap.Conn, userDN string) ([]string, error) {
+	result, err := conn.Search(ldap.NewSearchRequest(
+		b.GroupSearchBase, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, int(b.Timeout.Seconds()), false,
+		fmt.Sprintf(b.GroupSearchFilter, ldap.EscapeFilter(userDN)),
+		[]string{"dn"},
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("ldap group lookup failed: %w", err)
+	}
+
+	groupDNs := make([]string, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		groupDNs = append(groupDNs, entry.DN)
+	}
+	return groupDNs, nil
+}
+
+// names of the django-auth groups mapped to the DNs, DNs are compared case insensitively
+func (b *LDAPAuthBackend) mappedGroupNames(groupDNs []string) []string {
+	names := []string{}
+	for mappedDN, name := range b.GroupMap {
+		mapped, err := ldap.ParseDN(mappedDN)
+		if err != nil {
+			continue
+		}
+		for _, groupDN := range groupDNs {
+			if parsed, err := ldap.ParseDN(groupDN); err == nil && mapped.EqualFold(parsed) {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// creates the user on first login and updates the directory attributes and mapped groups
+func (b *LDAPAuthBackend) syncUser(ctx context.Context, username string, entry *ldap.Entry, groupDNs []string) (*models.User, error) {
+	var user models.User
+	// the directory ignores case like the username index does, a live user comes before one in the trash
+	err := b.Users.FindOne(ctx, bson.M{"username": username},
+		options.FindOne().SetCollation(caseInsensitiveCollation).SetSort(bson.D{{Key: "deleted_at", Value: 1}})).Decode(&user)
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		// the password stays in the directory, the local one can never match
+		password, err := utils.MakeUnusablePassword()
+		if err != nil {
+			return nil, err
+		}
+		user = models.User{
+			ID:        primitive.NewObjectID(),
+			Password:  password,
+			Username:  username,
+			IsActive:  true,
+			Backend:   LDAPAuthBackendName,
+			Version:   1,
+			CreatedAt: time.Now(),
+		}
+		err = audited(ctx, b.Users, models.ObjectTypeUser, models.AuditActionCreate, user.ID, func(sc mongo.SessionContext) error {
+			_, err := b.Users.InsertOne(sc, user)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("insert failed: %w", err)
+		}
+	case err != nil:
+		return nil, err
+	case user.Backend != LDAPAuthBackendName:
+		// a local account with the same username is never taken over by the directory
+		return nil, ErrInvalidCredentials
+	case user.DeletedAt != nil:
+		// the account is in the trash, restoring it lets the directory user in again
+		return nil, ErrInvalidCredentials
+	}
+
+	// every group named in the map is managed by the directory, other groups are left alone
+	managedNames := make([]string, 0, len(b.GroupMap))
+	for _, name := range b.GroupMap {
+		managedNames = append(managedNames, name)
+	}
+	cursor, err := b.Groups.Find(ctx, notDeleted(bson.M{"name": bson.M{"$in": managedNames}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch groups: %w", err)
+	}
+	var managedGroups []models.Group
+	if err := cursor.All(ctx, &managedGroups); err != nil {
+		return nil, fmt.Errorf("failed to decode groups: %w", err)
+	}
+
+	grantedNames := b.mappedGroupNames(groupDNs)
+	groupIDs := []primitive.ObjectID{}
+	for _, groupID := range user.GroupIDs {
+		if !slices.ContainsFunc(managedGroups, func(group models.Group) bool { return group.ID == groupID }) {
+			groupIDs = append(groupIDs, groupID)
+		}
+	}
+	for _, group := range managedGroups {
+		if slices.Contains(grantedNames, group.Name) {
+			groupIDs = append(groupIDs, group.ID)
+		}
+	}
+
+	user.FirstName = entry.GetEqualFoldAttributeValue(b.FirstNameAttribute)
+	user.LastName = entry.GetEqualFoldAttributeValue(b.LastNameAttribute)
+	user.Email = entry.GetEqualFoldAttributeValue(b.EmailAttribute)
+	user.GroupIDs = groupIDs
+	user.UpdatedAt = time.Now()
+
+	// the directory owns these fields, the version moves on like any other update so an ETag
+	// read before the login can not overwrite what the directory just wrote
+	err = audited(ctx, b.Users, models.ObjectTypeUser, models.AuditActionUpdate, user.ID, func(sc mongo.SessionContext) error {
+		_, err := b.Users.UpdateOne(sc, bson.M{"_id": user.ID}, bson.M{
+			"$set": bson.M{
+				"first_name": user.FirstName,
+				"last_name":  user.LastName,
+				"email":      user.Email,
+				"group_ids":  user.GroupIDs,
+				"updated_at": user.UpdatedAt,
+			},
+			"$inc": incrementVersion,
+		})
+		return err
+	})
+	if err != nil {
+		// the directory email can be the email of a local user
+		if conflict := uniqueConflict(err); conflict != nil {
+			return nil, conflict
+		}
+		return nil, fmt.Errorf("update failed: %w", err)
+	}
+	user.Version++
+
+	// group changes change the effective permissions
+	AppCacheService.Delete("user:" + user.ID.Hex())
+	invalidateEffectivePermissions()
+
+	return &user, nil
+}
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/cache"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// ##########################################################
+// ##########  In-process LDAP Directory
+// ##########################################################
+
+type testLDAPEntry struct {
+	password   string
+	attributes map[string][]string
+}
+
+// testDirectory answers simple binds and base object searches over a net.Pipe,
+// which is all the ldap backend sends
+type testDirectory struct {
+	entries map[string]testLDAPEntry
+	dials   atomic.Int32
+}
+
+func (d *testDirectory) dial(ctx context.Context) (*ldap.Conn, error) {
+	d.dials.Add(1)
+	client, server := net.Pipe()
+	go d.serve(server)
+
+	conn := ldap.NewConn(client, false)
+	conn.Start()
+	return conn, nil
+}
+
+func (d *testDirectory) serve(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		request, err := ber.ReadPacket(conn)
+		if err != nil || len(request.Children) < 2 {
+			return
+		}
+		messageID := request.Children[0].Value.(int64)
+		operation := request.Children[1]
+
+		switch operation.Tag {
+		case ldap.ApplicationBindRequest:
+			dn := operation.Children[1].Value.(string)
+			password := operation.Children[2].Data.String()
+			code := uint16(ldap.LDAPResultSuccess)
+			if entry, ok := d.entries[dn]; !ok || entry.password != password {
+				code = ldap.LDAPResultInvalidCredentials
+			}
+			conn.Write(testLDAPResponse(messageID, testLDAPResult(ldap.ApplicationBindResponse, code)).Bytes())
+		case ldap.ApplicationSearchRequest:
+			dn := operation.Children[0].Value.(string)
+			if entry, ok := d.entries[dn]; ok {
+				conn.Write(testLDAPResponse(messageID, testLDAPSearchEntry(dn, entry.attributes)).Bytes())
+			}
+			conn.Write(testLDAPResponse(messageID, testLDAPResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess)).Bytes())
+		default:
+			// unbind or anything the backend does not send
+			return
+		}
+	}
+}
+
+func testLDAPResponse(messageID int64, operation *ber.Packet) *ber.Packet {
+	envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	envelope.AppendChild(operation)
+	return envelope
+}
+
+func testLDAPResult(application int, code uint16) *ber.Packet {
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ber.Tag(application), nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return result
+}
+
+func testLDAPSearchEntry(dn string, attributes map[string][]string) *ber.Packet {
+	entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, "Object Name"))
+
+	list := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for name, values := range attributes {
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+		attribute.AppendChild(set)
+		list.AppendChild(attribute)
+	}
+	entry.AppendChild(list)
+	return entry
+}
+
+// ##########################################################
+// ##########  LDAP Auth Backend Tests
+// ##########################################################
+
+const (
+	testAliceDN  = "uid=alice,ou=people,dc=example,dc=com"
+	testAdminsDN = "cn=admins,ou=groups,dc=example,dc=com"
+)
+
+func newTestLDAPBackend(directory *testDirectory) *LDAPAuthBackend {
+	return &LDAPAuthBackend{
+		Dial:               directory.dial,
+		UserDNTemplate:     "uid=%s,ou=people,dc=example,dc=com",
+		FirstNameAttribute: "givenName",
+		LastNameAttribute:  "sn",
+		EmailAttribute:     "mail",
+		GroupAttribute:     "memberOf",
+		// keys differ in case from the directory values on purpose
+		GroupMap: map[string]string{
+			"CN=Admins,OU=Groups,DC=example,DC=com": "admins",
+			"CN=Ops,OU=Groups,DC=example,DC=com":    "ops",
+		},
+		Timeout: 5 * time.Second,
+	}
+}
+
+func newTestDirectory() *testDirectory {
+	return &testDirectory{entries: map[string]testLDAPEntry{
+		testAliceDN: {
+			password: "wonderland",
+			attributes: map[string][]string{
+				"givenName": {"Alice"},
+				"sn":        {"Liddell"},
+				"mail":      {"alice@example.com"},
+				"memberOf":  {testAdminsDN},
+			},
+		},
+	}}
+}
+
+// points the package services used by syncUser at the mock client
+func useMockServices(mt *mtest.T) {
+	database := mt.Client.Database("django_auth")
+	HandlerAuditLogService = AuditLogService{
+		Collection: database.Collection("AuditLogs"),
+		Client:     mt.Client,
+		Database:   database,
+	}
+
+	if AppCacheService == nil {
+		var err error
+		if AppCacheService, err = cache.NewCacheService(); err != nil {
+			mt.Fatalf("cache service: %v", err)
+		}
+	}
+}
+
+func TestLDAPAuthenticateRejectsBeforeSync(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		password string
+		dials    int32
+	}{
+		{name: "empty password is never sent as an unauthenticated bind", username: "alice", password: "", dials: 0},
+		{name: "empty username", username: "", password: "wonderland", dials: 0},
+		{name: "wrong password", username: "alice", password: "looking-glass", dials: 1},
+		{name: "unknown user", username: "bob", password: "wonderland", dials: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directory := newTestDirectory()
+			backend := newTestLDAPBackend(directory)
+
+			user, err := backend.Authenticate(context.Background(), tt.username, tt.password)
+			if !errors.Is(err, ErrInvalidCredentials) {
+				t.Fatalf("Authenticate() = %v, %v, want ErrInvalidCredentials", user, err)
+			}
+			if dials := directory.dials.Load(); dials != tt.dials {
+				t.Errorf("directory dialed %d times, want %d", dials, tt.dials)
+			}
+		})
+	}
+}
+
+func TestLDAPAuthenticateCreatesUserWithMappedGroups(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("first login", func(mt *mtest.T) {
+		useMockServices(mt)
+		database := mt.Client.Database("django_auth")
+		backend := newTestLDAPBackend(newTestDirectory())
+		backend.Users = database.Collection("Users")
+		backend.Groups = database.Collection("Groups")
+
+		adminsID, opsID := primitive.NewObjectID(), primitive.NewObjectID()
+		stored := bson.D{{Key: "username", Value: "alice"}, {Key: "backend", Value: LDAPAuthBackendName}}
+		synced := bson.D{
+			{Key: "username", Value: "alice"},
+			{Key: "backend", Value: LDAPAuthBackendName},
+			{Key: "group_ids", Value: bson.A{adminsID}},
+		}
+
+		mt.AddMockResponses(
+			// no user with the username yet
+			mtest.CreateCursorResponse(0, "django_auth.Users", mtest.FirstBatch),
+			// audited insert, snapshot before, insert, snapshot after, audit entry, commit
+			mtest.CreateCursorResponse(0, "django_auth.Users", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCursorResponse(0, "django_auth.Users", mtest.FirstBatch, stored),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			// the groups managed by the map
+			mtest.CreateCursorResponse(0, "django_auth.Groups", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: adminsID}, {Key: "name", Value: "admins"}},
+				bson.D{{Key: "_id", Value: opsID}, {Key: "name", Value: "ops"}},
+			),
+			// audited update of the directory attributes
+			mtest.CreateCursorResponse(0, "django_auth.Users", mtest.FirstBatch, stored),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateCursorResponse(0, "django_auth.Users", mtest.FirstBatch, synced),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+
+		user, err := backend.Authenticate(context.Background(), "alice", "wonderland")
+		if err != nil {
+			mt.Fatalf("Authenticate() error = %v", err)
+		}
+		if user.Backend != LDAPAuthBackendName || user.Username != "alice" {
+			mt.Errorf("user = %s from %q, want alice from %q", user.Username, user.Backend, LDAPAuthBackendName)
+		}
+		if user.FirstName != "Alice" || user.LastName != "Liddell" || user.Email != "alice@example.com" {
+			mt.Errorf("attributes = %q %q %q, want the directory values", user.FirstName, user.LastName, user.Email)
+		}
+		if !slices.Equal(user.GroupIDs, []primitive.ObjectID{adminsID}) {
+			mt.Errorf("GroupIDs = %v, want only admins %v", user.GroupIDs, adminsID)
+		}
+	})
+}
+
+func TestLDAPSyncUserReplacesOnlyManagedGroups(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("returning user", func(mt *mtest.T) {
+		useMockServices(mt)
+		database := mt.Client.Database("django_auth")
+		backend := newTestLDAPBackend(newTestDirectory())
+		backend.Users = database.Collection("Users")
+		backend.Groups = database.Collection("Groups")
+
+		userID := primitive.NewObjectID()
+		adminsID, opsID, localID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+		stored := bson.D{
+			{Key: "_id", Value: userID},
+			{Key: "username", Value: "alice"},
+			{Key: "backend", Value: LDAPAuthBackendName},
+			{Key: "group_ids", Value: bson.A{localID, opsID}},
+		}
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "django_auth.Users", mtest.FirstBatch, stored),
+			mtest.CreateCursorResponse(0, "django_auth.Groups", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: adminsID}, {Key: "name", Value: "admins"}},
+				bson.D{{Key: "_id", Value: opsID}, {Key: "name", Value: "ops"}},
+			),
+			mtest.CreateCursorResponse(0, "django_auth.Users", mtest.FirstBatch, stored),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateCursorResponse(0, "django_auth.Users", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: userID},
+				{Key: "group_ids", Value: bson.A{localID, adminsID}},
+			}),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+
+		entry := ldap.NewEntry(testAliceDN, map[string][]string{"mail": {"alice@example.com"}})
+		// the DN comes back in a different case than the map key
+		user, err := backend.syncUser(context.Background(), "alice", entry, []string{"CN=ADMINS,ou=groups,dc=example,dc=com"})
+		if err != nil {
+			mt.Fatalf("syncUser() error = %v", err)
+		}
+		if user.ID != userID {
+			mt.Errorf("ID = %v, want the existing user %v", user.ID, userID)
+		}
+		// ops is managed but no longer granted, the local group stays
+		if want := []primitive.ObjectID{localID, adminsID}; !slices.Equal(user.GroupIDs, want) {
+			mt.Errorf("GroupIDs = %v, want %v", user.GroupIDs, want)
+		}
+	})
+}
+
+func TestLDAPSyncUserEmailTaken(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("email of a local user", func(mt *mtest.T) {
+		useMockServices(mt)
+		database := mt.Client.Database("django_auth")
+		backend := newTestLDAPBackend(newTestDirectory())
+		backend.Users = database.Collection("Users")
+		backend.Groups = database.Collection("Groups")
+
+		stored := bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "username", Value: "alice"},
+			{Key: "backend", Value: LDAPAuthBackendName},
+		}
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "django_auth.Users", mtest.FirstBatch, stored),
+			mtest.CreateCursorResponse(0, "django_auth.Groups", mtest.FirstBatch),
+			// audited update, snapshot before, the update hits the email index, abort
+			mtest.CreateCursorResponse(0, "django_auth.Users", mtest.FirstBatch, stored),
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{
+				Code:    11000,
+				Message: `E11000 duplicate key error collection: django_auth.Users index: email_1_deleted_at_1 dup key: { email: "alice@example.com", deleted_at: null }`,
+			}),
+			mtest.CreateSuccessResponse(),
+		)
+
+		entry := ldap.NewEntry(testAliceDN, map[string][]string{"mail": {"alice@example.com"}})
+		user, err := backend.syncUser(context.Background(), "alice", entry, nil)
+		if !errors.Is(err, ErrEmailTaken) {
+			mt.Fatalf("syncUser() = %v, %v, want ErrEmailTaken", user, err)
+		}
+	})
+}
+
+func TestLDAPSyncUserDoesNotTakeOverLocalAccount(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("local account", func(mt *mtest.T) {
+		useMockServices(mt)
+		backend := newTestLDAPBackend(newTestDirectory())
+		backend.Users = mt.Client.Database("django_auth").Collection("Users")
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "django_auth.Users", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "username", Value: "Alice"},
+		}))
+
+		user, err := backend.Authenticate(context.Background(), "alice", "wonderland")
+		if !errors.Is(err, ErrInvalidCredentials) {
+			mt.Fatalf("Authenticate() = %v, %v, want ErrInvalidCredentials", user, err)
+		}
+		// nothing was written to the local account
+		for _, event := range mt.GetAllStartedEvents() {
+			if event.CommandName != "find" {
+				mt.Errorf("unexpected %s command", event.CommandName)
+			}
+		}
+	})
+}
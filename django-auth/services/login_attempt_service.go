@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/observe"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var HandlerLoginAttemptService LoginAttemptService
+
+var ErrLoginLocked = errors.New("too many failed login attempts, try again later")
+
+// LoginAttemptService tracks failed logins per username and per client ip
+type LoginAttemptService struct {
+	Collection *mongo.Collection
+	Client     *mongo.Client
+	Database   *mongo.Database
+}
+
+// Constructor For Client
+func NewLoginAttemptService(client *mongo.Client) (*LoginAttemptService, error) {
+	database := client.Database("django_auth")
+	collection := database.Collection("LoginAttempts")
+	HandlerLoginAttemptService = LoginAttemptService{
+		Collection: collection,
+		Client:     client,
+		Database:   database,
+	}
+	return &HandlerLoginAttemptService, nil
+}
+
+// EnsureIndexes creates the key index and lets mongo drop counters once they expired
+func (s *LoginAttemptService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+// lockout thresholds, a scope is locked once its failures reach the limit
+type lockoutPolicy struct {
+	scope       string
+	maxFailures int
+}
+
+func configInt(key string, fallback int) int {
+	value, err := strconv.Atoi(configs.AppConfig.GetOrDefault(key, strconv.Itoa(fallback)))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// usernames compare ignoring case, so Alice and alice share one counter
+func usernameAttemptKey(username string) string {
+	return "username:" + strings.ToLower(username)
+}
+
+// counters are keyed by scope so a username and an ip never collide
+func loginAttemptKeys(username, ip string) map[string]lockoutPolicy {
+	keys := map[string]lockoutPolicy{}
+	if username != "" {
+		keys[usernameAttemptKey(username)] = lockoutPolicy{"username", configInt("LOGIN_MAX_FAILURES_PER_USERNAME", 5)}
+	}
+	if ip != "" {
+		keys["ip:"+ip] = lockoutPolicy{"ip", configInt("LOGIN_MAX_FAILURES_PER_IP", 20)}
+	}
+	return keys
+}
+
+// the lockout doubles with every failure past the limit, capped at LOGIN_LOCKOUT_MAX_SECONDS
+func lockoutDuration(failures, maxFailures int) time.Duration {
+	base := time.Duration(configInt("LOGIN_LOCKOUT_BASE_SECONDS", 30)) * time.Second
+	limit := time.Duration(configInt("LOGIN_LOCKOUT_MAX_SECONDS", 3600)) * time.Second
+
+	lockout := base
+	for i := maxFailures; i < failures && lockout < limit; i++ {
+		lockout *= 2
+	}
+	return min(lockout, limit)
+}
+
+// Check returns ErrLoginLocked and the time left when the username or the ip is locked
+func (s *LoginAttemptService) Check(ctx context.Context, username, ip string) (time.Duration, error) {
+	keys := make([]string, 0, 2)
+	for key := range loginAttemptKeys(username, ip) {
+		keys = append(keys, key)
+	}
+
+	now := time.Now()
+	cursor, err := s.Collection.Find(ctx, bson.M{"key": bson.M{"$in": keys}, "locked_until": bson.M{"$gt": now}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch login attempts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var retryAfter time.Duration
+	for cursor.Next(ctx) {
+		var attempt models.LoginAttempt
+		if err := cursor.Decode(&attempt); err != nil {
+			return 0, fmt.Errorf("failed to decode login attempt: %w", err)
+		}
+		retryAfter = max(retryAfter, attempt.LockedUntil.Sub(now))
+	}
+
+	if retryAfter > 0 {
+		observe.LoginFailures.WithLabelValues("locked", configs.AppConfig.Get("APP_NAME")).Inc()
+		return retryAfter, ErrLoginLocked
+	}
+	return 0, nil
+}
+
+// RecordFailure counts a failed attempt for the username and the ip and locks whichever hit its limit
+func (s *LoginAttemptService) RecordFailure(ctx context.Context, username, ip, reason string) error {
+	observe.LoginFailures.WithLabelValues(reason, configs.AppConfig.Get("APP_NAME")).Inc()
+
+	now := time.Now()
+	window := time.Duration(configInt("LOGIN_FAILURE_WINDOW_MINUTES", 15)) * time.Minute
+
+	for key, policy := range loginAttemptKeys(username, ip) {
+		// counting restarts when the previous failures are older than the window
+		update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"key": key,
+			"failures": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$expires_at", now}},
+				bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$failures", 0}}, 1}},
+				1,
+			}},
+			"last_failure_at": now,
+			"expires_at":      bson.M{"$max": bson.A{"$locked_until", now.Add(window)}},
+		}}}}
+		opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+		var attempt models.LoginAttempt
+		err := s.Collection.FindOneAndUpdate(ctx, bson.M{"key": key}, update, opts).Decode(&attempt)
+		if mongo.IsDuplicateKeyError(err) {
+			// a concurrent failure inserted the counter first, the retry updates it
+			err = s.Collection.FindOneAndUpdate(ctx, bson.M{"key": key}, update, opts).Decode(&attempt)
+		}
+		if err != nil {
+			return fmt.Errorf("record login failure failed: %w", err)
+		}
+
+		if attempt.Failures < policy.maxFailures {
+			continue
+		}
+
+		lockedUntil := now.Add(lockoutDuration(attempt.Failures, policy.maxFailures))
+		_, err = s.Collection.UpdateOne(ctx, bson.M{"_id": attempt.ID}, bson.M{
+			"$set": bson.M{"locked_until": lockedUntil, "expires_at": lockedUntil.Add(window)},
+		})
+		if err != nil {
+			return fmt.Errorf("lock login failed: %w", err)
+		}
+		observe.LoginLockouts.WithLabelValues(policy.scope, configs.AppConfig.Get("APP_NAME")).Inc()
+	}
+
+	return nil
+}
+
+// RecordSuccess clears the username counter, the ip counter is kept so one good
+// account does not reset a credential stuffing run
+func (s *LoginAttemptService) RecordSuccess(ctx context.Context, username string) error {
+	_, err := s.Collection.DeleteOne(ctx, bson.M{"key": usernameAttemptKey(username)})
+	return err
+}
+
+// Unlock clears the counters and lockouts of the username and/or ip
+func (s *LoginAttemptService) Unlock(ctx context.Context, username, ip string) (int64, error) {
+	keys := make([]string, 0, 2)
+	for key := range loginAttemptKeys(username, ip) {
+		keys = append(keys, key)
+	}
+
+	result, err := s.Collection.DeleteMany(ctx, bson.M{"key": bson.M{"$in": keys}})
+	if err != nil {
+		return 0, fmt.Errorf("unlock failed: %w", err)
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrMFAInvalidCode    = errors.New("invalid or already used verification code")
+	ErrMFANotEnrolling   = errors.New("no pending MFA enrollment, start enrollment first")
+	ErrMFAAlreadyEnabled = errors.New("MFA is already enabled")
+	ErrMFANotEnabled     = errors.New("MFA is not enabled")
+	ErrMFAUserID         = errors.New("invalid user ID")
+)
+
+// number of recovery codes handed out when MFA is enabled
+const recoveryCodeCount = 10
+
+// ##########################################################
+// ##########  TOTP Multi Factor Services
+// ##########################################################
+
+// fetches the full user document including the MFA fields
+func (s *UserService) findUser(ctx context.Context, userID string) (*models.User, error) {
+	user_id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var user models.User
+	if err := s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": user_id})).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+	return &user, nil
+}
+
+// MFARequired reports whether the user has to pass a second factor, or enroll one, to login
+func MFARequired(user *models.User) bool {
+	if user.MFAEnabled {
+		return true
+	}
+	required := configs.AppConfig.GetOrDefault("MFA_REQUIRED_FOR_STAFF", "false") == "true"
+	return required && (user.IsStaff || user.IsSuperuser)
+}
+
+// BeginMFAEnrollment stores a pending secret and returns it with its otpauth uri
+func (s *UserService) BeginMFAEnrollment(ctx context.Context, userID string) (*models.MFAEnrollment, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	err = audited(ctx, s.Collection, models.ObjectTypeUser, models.AuditActionUpdate, user.ID, func(sc mongo.SessionContext) error {
+		_, err := s.Collection.UpdateOne(sc, bson.M{"_id": user.ID}, bson.M{
+			"$set": bson.M{"mfa_pending_secret": secret},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update failed: %w", err)
+	}
+
+	account := user.Email
+	if account == "" {
+		account = user.Username
+	}
+
+	return &models.MFAEnrollment{
+		Secret: secret,
+		URI:    utils.TOTPURI(configs.AppConfig.GetOrDefault("MFA_ISSUER", "django_auth"), account, secret),
+	}, nil
+}
+
+// ConfirmMFAEnrollment enables MFA once the user proves the pending secret works
+// and returns freshly generated recovery codes
+func (s *UserService) ConfirmMFAEnrollment(ctx context.Context, userID string, code string) ([]string, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.MFAPendingSecret == "" {
+		return nil, ErrMFANotEnrolling
+	}
+
+	step, ok := utils.ValidateTOTP(user.MFAPendingSecret, code, time.Now())
+	if !ok {
+		return nil, ErrMFAInvalidCode
+	}
+
+	recoveryCodes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	hashedCodes := make([]string, 0, len(recoveryCodes))
+	for _, recoveryCode := range recoveryCodes {
+		hashedCodes = append(hashedCodes, utils.HashRecoveryCode(recoveryCode))
+	}
+
+	// the pending secret is matched in the filter so a restarted enrollment can not be confirmed with an old code
+	err = audited(ctx, s.Collection, models.ObjectTypeUser, models.AuditActionUpdate, user.ID, func(sc mongo.SessionContext) error {
+		result, err := s.Collection.UpdateOne(sc, bson.M{"_id": user.ID, "mfa_pending_secret": user.MFAPendingSecret}, bson.M{
+			"$set": bson.M{
+				"mfa_enabled":        true,
+				"mfa_secret":         user.MFAPendingSecret,
+				"mfa_recovery_codes": hashedCodes,
+				"mfa_last_step":      step,
+				"updated_at":         time.Now(),
+			},
+			"$unset": bson.M{"mfa_pending_secret": ""},
+			"$inc":   incrementVersion,
+		})
+		if err != nil {
+			return fmt.Errorf("update failed: %w", err)
+		}
+		if result.MatchedCount == 0 {
+			return ErrMFANotEnrolling
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Removing Cache since mfa status changed
+	AppCacheService.Delete("user:" + userID)
+
+	return recoveryCodes, nil
+}
+
+// VerifyMFA checks a TOTP code or burns one recovery code
+func (s *UserService) VerifyMFA(ctx context.Context, userID string, code string) error {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+
+	// the second factor is checked during the login, before the user holds a token
+	ctx = withUserAsActor(ctx, user.ID.Hex(), user.Username)
+
+	// TOTP codes are accepted once, the last used step is compared atomically
+	if step, ok := utils.ValidateTOTP(user.MFASecret, code, time.Now()); ok {
+		return audited(ctx, s.Collection, models.ObjectTypeUser, models.AuditActionUpdate, user.ID, func(sc mongo.SessionContext) error {
+			result, err := s.Collection.UpdateOne(sc, bson.M{
+				"_id": user.ID,
+				"$or": bson.A{
+					bson.M{"mfa_last_step": bson.M{"$exists": false}},
+					bson.M{"mfa_last_step": bson.M{"$lt": step}},
+				},
+			}, bson.M{"$set": bson.M{"mfa_last_step": step}})
+			if err != nil {
+				return err
+			}
+			if result.ModifiedCount == 0 {
+				return ErrMFAInvalidCode
+			}
+			return nil
+		})
+	}
+
+	// falling back to recovery codes, each one can be pulled only once
+	hashedCode := utils.HashRecoveryCode(code)
+	if !slices.Contains(user.MFARecoveryCodes, hashedCode) {
+		return ErrMFAInvalidCode
+	}
+
+	return audited(ctx, s.Collection, models.ObjectTypeUser, models.AuditActionUpdate, user.ID, func(sc mongo.SessionContext) error {
+		result, err := s.Collection.UpdateOne(sc,
+			bson.M{"_id": user.ID, "mfa_recovery_codes": hashedCode},
+			bson.M{"$pull": bson.M{"mfa_recovery_codes": hashedCode}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.ModifiedCount == 0 {
+			return ErrMFAInvalidCode
+		}
+		return nil
+	})
+}
+
+// ResetMFA turns MFA off for a locked out user, they enroll again on next login if required
+func (s *UserService) ResetMFA(ctx context.Context, userID string) error {
+	user_id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMFAUserID, err)
+	}
+
+	err = audited(ctx, s.Collection, models.ObjectTypeUser, models.AuditActionUpdate, user_id, func(sc mongo.SessionContext) error {
+		result, err := s.Collection.UpdateOne(sc, bson.M{"_id": user_id}, bson.M{
+			"$set": bson.M{"mfa_enabled": false, "updated_at": time.Now()},
+			"$unset": bson.M{
+				"mfa_secret":         "",
+				"mfa_pending_secret": "",
+				"mfa_recovery_codes": "",
+				"mfa_last_step":      "",
+			},
+			"$inc": incrementVersion,
+		})
+		if err != nil {
+			return fmt.Errorf("update failed: %w", err)
+		}
+		if result.MatchedCount == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Removing Cache since mfa status changed
+	AppCacheService.Delete("user:" + userID)
+
+	return nil
+}
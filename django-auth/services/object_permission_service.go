@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/jinzhu/copier"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var HandlerObjectPermissionService ObjectPermissionService
+
+var (
+	ErrObjectPermissionExists = errors.New("the permission is already granted on this object")
+	ErrObjectPermissionTarget = errors.New("the permission, user, group or object does not exist")
+)
+
+// collections holding the documents of each object type
+var objectPermissionCollections = map[string]string{
+	models.ObjectTypeUser:       "Users",
+	models.ObjectTypeGroup:      "Groups",
+	models.ObjectTypePermission: "Permissions",
+}
+
+// ObjectPermissionService wraps MongoDB logic for permissions granted on single documents
+type ObjectPermissionService struct {
+	Collection *mongo.Collection
+	Client     *mongo.Client
+	Database   *mongo.Database
+}
+
+// Constructor For Client
+func NewObjectPermissionService(client *mongo.Client) (*ObjectPermissionService, error) {
+	database := client.Database("django_auth")
+	collection := database.Collection("ObjectPermissions")
+	HandlerObjectPermissionService = ObjectPermissionService{
+		Collection: collection,
+		Client:     client,
+		Database:   database,
+	}
+	return &HandlerObjectPermissionService, nil
+}
+
+// EnsureIndexes makes grants unique and indexes the lookup by object
+func (s *ObjectPermissionService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// the missing grantee is indexed as null so user and group grants do not collide
+			Keys:    bson.D{{Key: "permission_id", Value: 1}, {Key: "object_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "group_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "object_id", Value: 1}, {Key: "permission_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "group_id", Value: 1}}},
+	})
+	return err
+}
+
+// counts documents matching the id in the named collection
+func (s *ObjectPermissionService) exists(ctx context.Context, collection string, id primitive.ObjectID) (bool, error) {
+	count, err := s.Database.Collection(collection).CountDocuments(ctx, notDeleted(bson.M{"_id": id}), options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+// Create grants the permission to the user or group on the object, every referenced document must exist
+func (s *ObjectPermissionService) Create(ctx context.Context, posted_grant *models.ObjectPermissionPost) (*models.ObjectPermissionGet, error) {
+	collection, ok := objectPermissionCollections[posted_grant.ObjectType]
+	if !ok {
+		return nil, fmt.Errorf("unknown object type: %s", posted_grant.ObjectType)
+	}
+
+	grant := models.ObjectPermission{
+		ID:         primitive.NewObjectID(),
+		ObjectType: posted_grant.ObjectType,
+		CreatedAt:  time.Now(),
+	}
+
+	var err error
+	if grant.PermissionID, err = primitive.ObjectIDFromHex(posted_grant.PermissionID); err != nil {
+		return nil, fmt.Errorf("%w: invalid permission ID", ErrObjectPermissionTarget)
+	}
+	if grant.ObjectID, err = primitive.ObjectIDFromHex(posted_grant.ObjectID); err != nil {
+		return nil, fmt.Errorf("%w: invalid object ID", ErrObjectPermissionTarget)
+	}
+
+	references := map[primitive.ObjectID]string{
+		grant.PermissionID: "Permissions",
+		grant.ObjectID:     collection,
+	}
+	if posted_grant.UserID != "" {
+		userID, err := primitive.ObjectIDFromHex(posted_grant.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid user ID", ErrObjectPermissionTarget)
+		}
+		grant.UserID = &userID
+		references[userID] = "Users"
+	} else {
+		groupID, err := primitive.ObjectIDFromHex(posted_grant.GroupID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid group ID", ErrObjectPermissionTarget)
+		}
+		grant.GroupID = &groupID
+		references[groupID] = "Groups"
+	}
+
+	for id, collection := range references {
+		found, err := s.exists(ctx, collection, id)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, ErrObjectPermissionTarget
+		}
+	}
+
+	if _, err := s.Collection.InsertOne(ctx, grant); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrObjectPermissionExists
+		}
+		return nil, fmt.Errorf("insert failed: %w", err)
+	}
+
+	createdGrant := new(models.ObjectPermissionGet)
+	if err := copier.CopyWithOption(createdGrant, grant, copier.Option{DeepCopy: true}); err != nil {
+		return nil, err
+	}
+	return createdGrant, nil
+}
+
+// GetOne fetches a grant by ID
+func (s *ObjectPermissionService) GetOne(ctx context.Context, id string) (*models.ObjectPermissionGet, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID: %w", err)
+	}
+
+	var grant models.ObjectPermissionGet
+	if err := s.Collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&grant); err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// Get returns grants with pagination, filters hold exact values for permission_id, user_id, group_id,
+// object_type and object_id
+func (s *ObjectPermissionService) Get(ctx context.Context, pagination models.Pagination, filters map[string]string) ([]models.ObjectPermissionGet, uint, error) {
+	filter := bson.M{}
+	for field, value := range filters {
+		if value == "" {
+			continue
+		}
+		if field == "object_type" {
+			filter[field] = value
+			continue
+		}
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid %s: %w", field, err)
+		}
+		filter[field] = id
+	}
+
+	//pagination logic
+	opts := options.Find().
+		SetSkip(int64(pagination.Page * pagination.Size)).
+		SetLimit(int64(pagination.Size))
+
+	totalCount, _ := s.Collection.CountDocuments(ctx, filter)
+
+	cursor, err := s.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, uint(totalCount), err
+	}
+	defer cursor.Close(ctx)
+
+	var grants []models.ObjectPermissionGet
+	for cursor.Next(ctx) {
+		var g models.ObjectPermissionGet
+		if err := cursor.Decode(&g); err != nil {
+			return nil, uint(totalCount), err
+		}
+		grants = append(grants, g)
+	}
+
+	return grants, uint(totalCount), nil
+}
+
+// Delete removes a grant by ID
+func (s *ObjectPermissionService) Delete(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %w", err)
+	}
+
+	result, err := s.Collection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("no document deleted")
+	}
+	return nil
+}
+
+// deleteReferencing removes the grants to a deleted user, group or permission and the grants on it,
+// field is user_id, group_id or permission_id
+func (s *ObjectPermissionService) deleteReferencing(ctx context.Context, field, objectType string, id primitive.ObjectID) error {
+	_, err := s.Collection.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{field: id},
+		bson.M{"object_type": objectType, "object_id": id},
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to delete object permissions: %w", err)
+	}
+	return nil
+}
+
+// HasObjectPermission reports whether the user holds the permission on the object, active superusers hold every
+// permission. Global permissions are not consulted, check those first as the auth middleware does.
+func (s *ObjectPermissionService) HasObjectPermission(ctx context.Context, userID, codename, objectID string) (bool, error) {
+	user, err := HandlerUserService.findUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if !user.IsActive {
+		return false, nil
+	}
+	if user.IsSuperuser {
+		return true, nil
+	}
+	return s.hasGrant(ctx, user, codename, objectID)
+}
+
+// HasObjectGrant is HasObjectPermission without the superuser shortcut, for callers like impersonation
+// that must never get superuser powers
+func (s *ObjectPermissionService) HasObjectGrant(ctx context.Context, userID, codename, objectID string) (bool, error) {
+	user, err := HandlerUserService.findUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if !user.IsActive {
+		return false, nil
+	}
+	return s.hasGrant(ctx, user, codename, objectID)
+}
+
+// looks for a grant of the codename on the object to the user or one of the user's groups
+func (s *ObjectPermissionService) hasGrant(ctx context.Context, user *models.User, codename, objectID string) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(objectID)
+	if err != nil {
+		return false, nil
+	}
+
+	cursor, err := HandlerPermissionService.Collection.Find(ctx, notDeleted(bson.M{"codename": codename}),
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch permissions: %w", err)
+	}
+	var permissions []models.Permission
+	if err := cursor.All(ctx, &permissions); err != nil {
+		return false, fmt.Errorf("failed to decode permissions: %w", err)
+	}
+	if len(permissions) == 0 {
+		return false, nil
+	}
+
+	permissionIDs := make([]primitive.ObjectID, 0, len(permissions))
+	for _, permission := range permissions {
+		permissionIDs = append(permissionIDs, permission.ID)
+	}
+
+	grantees := bson.A{bson.M{"user_id": user.ID}}
+	if len(user.GroupIDs) > 0 {
+		// groups in the trash grant nothing
+		groups, err := existingIDs(ctx, HandlerGroupService.Collection, notDeleted(bson.M{"_id": bson.M{"$in": user.GroupIDs}}))
+		if err != nil {
+			return false, err
+		}
+		groupIDs := make([]primitive.ObjectID, 0, len(groups))
+		for id := range groups {
+			groupIDs = append(groupIDs, id)
+		}
+		grantees = append(grantees, bson.M{"group_id": bson.M{"$in": groupIDs}})
+	}
+
+	count, err := s.Collection.CountDocuments(ctx, bson.M{
+		"object_id":     objID,
+		"permission_id": bson.M{"$in": permissionIDs},
+		"$or":           grantees,
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to check object permission: %w", err)
+	}
+	return count > 0, nil
+}
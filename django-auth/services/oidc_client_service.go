@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var HandlerOIDCClientService OIDCClientService
+
+var ErrOIDCClientInvalid = errors.New("unknown client or invalid client credentials")
+
+// OIDCClientService wraps MongoDB logic for OpenID Connect relying parties
+type OIDCClientService struct {
+	Collection *mongo.Collection
+	Client     *mongo.Client
+	Database   *mongo.Database
+}
+
+// Constructor For Client
+func NewOIDCClientService(client *mongo.Client) (*OIDCClientService, error) {
+	database := client.Database("django_auth")
+	collection := database.Collection("OIDCClients")
+	HandlerOIDCClientService = OIDCClientService{
+		Collection: collection,
+		Client:     client,
+		Database:   database,
+	}
+	return &HandlerOIDCClientService, nil
+}
+
+// EnsureIndexes creates the client_id index
+func (s *OIDCClientService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "client_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// Create registers a client, confidential clients get a secret that is only returned here
+func (s *OIDCClientService) Create(ctx context.Context, posted_client *models.OIDCClientPost) (*models.OIDCClientCreated, error) {
+	clientID, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	client := models.OIDCClient{
+		ID:           primitive.NewObjectID(),
+		ClientID:     clientID.String(),
+		Name:         posted_client.Name,
+		RedirectURIs: posted_client.RedirectURIs,
+		Public:       posted_client.Public,
+		CreatedAt:    time.Now(),
+	}
+
+	createdClient := new(models.OIDCClientCreated)
+	if !client.Public {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("generate client secret failed: %w", err)
+		}
+		createdClient.ClientSecret = base64.RawURLEncoding.EncodeToString(secret)
+		client.ClientSecretHash = hashRandomToken(createdClient.ClientSecret)
+	}
+
+	if _, err := s.Collection.InsertOne(ctx, client); err != nil {
+		return nil, fmt.Errorf("insert failed: %w", err)
+	}
+
+	if err := copier.CopyWithOption(&createdClient.OIDCClientGet, client, copier.Option{DeepCopy: true}); err != nil {
+		return nil, err
+	}
+	return createdClient, nil
+}
+
+// GetOne fetches a client by ID
+func (s *OIDCClientService) GetOne(ctx context.Context, id string) (*models.OIDCClientGet, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID: %w", err)
+	}
+
+	var client models.OIDCClientGet
+	if err := s.Collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&client); err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// Get returns clients with pagination and search
+func (s *OIDCClientService) Get(ctx context.Context, pagination models.Pagination, searchFields []string, searchTerm []string) ([]models.OIDCClientGet, uint, error) {
+
+	// Build search query if any
+	filter := bson.M{}
+	if len(searchTerm) > 0 && len(searchFields) > 0 && len(searchFields) >= len(searchTerm) {
+		var orConditions []bson.M
+		for index, term := range searchTerm {
+			orConditions = append(orConditions, bson.M{
+				searchFields[index]: bson.M{"$regex": term, "$options": "i"},
+			})
+		}
+		filter["$or"] = orConditions
+	}
+
+	//pagination logic
+	opts := options.Find().
+		SetSkip(int64(pagination.Page * pagination.Size)).
+		SetLimit(int64(pagination.Size))
+
+	totalCount, _ := s.Collection.CountDocuments(ctx, filter)
+
+	cursor, err := s.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, uint(totalCount), err
+	}
+	defer cursor.Close(ctx)
+
+	var clients []models.OIDCClientGet
+	for cursor.Next(ctx) {
+		var c models.OIDCClientGet
+		if err := cursor.Decode(&c); err != nil {
+			return nil, uint(totalCount), err
+		}
+		clients = append(clients, c)
+	}
+
+	return clients, uint(totalCount), nil
+}
+
+// Update modifies a client by ID
+func (s *OIDCClientService) Update(ctx context.Context, patch_client *models.OIDCClientPatch, id string) (*models.OIDCClientGet, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID: %w", err)
+	}
+
+	updateFields := bson.M{"updated_at": time.Now()}
+	if patch_client.Name != nil {
+		updateFields["name"] = *patch_client.Name
+	}
+	if patch_client.RedirectURIs != nil {
+		updateFields["redirect_uris"] = *patch_client.RedirectURIs
+	}
+
+	var updatedClient models.OIDCClientGet
+	err = s.Collection.FindOneAndUpdate(ctx, bson.M{"_id": objID}, bson.M{"$set": updateFields},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updatedClient)
+	if err != nil {
+		return nil, fmt.Errorf("update failed: %w", err)
+	}
+
+	// Removing Cache if update sucess
+	AppCacheService.Delete("oidc_client:" + updatedClient.ClientID)
+
+	return &updatedClient, nil
+}
+
+// Delete removes a client by ID
+func (s *OIDCClientService) Delete(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %w", err)
+	}
+
+	var client models.OIDCClient
+	if err := s.Collection.FindOneAndDelete(ctx, bson.M{"_id": objID}).Decode(&client); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errors.New("no document deleted")
+		}
+		return err
+	}
+
+	// Removing Cache if delete sucess
+	AppCacheService.Delete("oidc_client:" + client.ClientID)
+
+	return nil
+}
+
+// FindByClientID looks a client up by its public client_id
+func (s *OIDCClientService) FindByClientID(ctx context.Context, clientID string) (*models.OIDCClient, error) {
+	cacheKey := "oidc_client:" + clientID
+	if cachedClient, found := AppCacheService.Get(cacheKey); found {
+		return cachedClient.(*models.OIDCClient), nil
+	}
+
+	var client models.OIDCClient
+	if err := s.Collection.FindOne(ctx, bson.M{"client_id": clientID}).Decode(&client); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrOIDCClientInvalid
+		}
+		return nil, err
+	}
+
+	AppCacheService.Set(cacheKey, &client)
+	return &client, nil
+}
+
+// AllowsRedirectURI reports whether the redirect uri was registered, compared exactly as OAuth requires
+func AllowsRedirectURI(client *models.OIDCClient, redirectURI string) bool {
+	return slices.Contains(client.RedirectURIs, redirectURI)
+}
+
+// VerifyClientSecret checks the secret of a confidential client, public clients have none
+func VerifyClientSecret(client *models.OIDCClient, secret string) bool {
+	if client.Public {
+		return secret == ""
+	}
+	hashed := hashRandomToken(secret)
+	return secret != "" && subtle.ConstantTimeCompare([]byte(hashed), []byte(client.ClientSecretHash)) == 1
+}
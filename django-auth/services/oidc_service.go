@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var HandlerAuthorizationCodeService AuthorizationCodeService
+
+var ErrAuthorizationCodeInvalid = errors.New("authorization code is invalid, expired or already used")
+
+// scopes the provider understands, openid is always required
+var OIDCScopes = []string{"openid", "profile", "email", "groups"}
+
+// AuthorizationCodeService wraps MongoDB logic for OIDC authorization codes
+type AuthorizationCodeService struct {
+	Collection *mongo.Collection
+	Client     *mongo.Client
+	Database   *mongo.Database
+}
+
+// Constructor For Client
+func NewAuthorizationCodeService(client *mongo.Client) (*AuthorizationCodeService, error) {
+	database := client.Database("django_auth")
+	collection := database.Collection("AuthorizationCodes")
+	HandlerAuthorizationCodeService = AuthorizationCodeService{
+		Collection: collection,
+		Client:     client,
+		Database:   database,
+	}
+	return &HandlerAuthorizationCodeService, nil
+}
+
+// EnsureIndexes creates the code index and lets mongo drop codes once they expired
+func (s *AuthorizationCodeService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "code_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+// Create stores the code for the authorization request and returns the raw code
+func (s *AuthorizationCodeService) Create(ctx context.Context, code *models.AuthorizationCode) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("generate code failed: %w", err)
+	}
+	rawCode := base64.RawURLEncoding.EncodeToString(secret)
+
+	seconds, _ := strconv.Atoi(configs.AppConfig.GetOrDefault("OIDC_CODE_SECONDS", "60"))
+	now := time.Now()
+	code.ID = primitive.NewObjectID()
+	code.CodeHash = hashRandomToken(rawCode)
+	code.ExpiresAt = now.Add(time.Duration(seconds) * time.Second)
+	code.CreatedAt = now
+
+	if _, err := s.Collection.InsertOne(ctx, code); err != nil {
+		return "", fmt.Errorf("insert failed: %w", err)
+	}
+	return rawCode, nil
+}
+
+// Consume marks the code used and returns it, a code can be exchanged once by the client it was issued to
+func (s *AuthorizationCodeService) Consume(ctx context.Context, rawCode string, clientID string, redirectURI string) (*models.AuthorizationCode, error) {
+	now := time.Now()
+
+	var code models.AuthorizationCode
+	err := s.Collection.FindOneAndUpdate(ctx, bson.M{
+		"code_hash":  hashRandomToken(rawCode),
+		"client_id":  clientID,
+		"expires_at": bson.M{"$gt": now},
+		"used_at":    bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{"used_at": now}}).Decode(&code)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrAuthorizationCodeInvalid
+		}
+		return nil, err
+	}
+
+	if code.RedirectURI != redirectURI {
+		return nil, ErrAuthorizationCodeInvalid
+	}
+	return &code, nil
+}
+
+// OIDCUserClaims builds the standard claims for the scopes granted, used by id tokens and userinfo
+func (s *UserService) OIDCUserClaims(ctx context.Context, userID string, scope string) (map[string]any, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrInactiveUser
+	}
+
+	scopes := strings.Fields(scope)
+	claims := map[string]any{"sub": user.ID.Hex()}
+
+	if slices.Contains(scopes, "profile") {
+		claims["preferred_username"] = user.Username
+		claims["given_name"] = user.FirstName
+		claims["family_name"] = user.LastName
+		claims["name"] = strings.TrimSpace(user.FirstName + " " + user.LastName)
+	}
+
+	if slices.Contains(scopes, "email") {
+		claims["email"] = user.Email
+	}
+
+	if slices.Contains(scopes, "groups") {
+		groups := []string{}
+		if len(user.GroupIDs) > 0 {
+			cursor, err := s.Database.Collection("Groups").Find(ctx, notDeleted(bson.M{"_id": bson.M{"$in": user.GroupIDs}}))
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch groups: %w", err)
+			}
+			var userGroups []models.Group
+			if err := cursor.All(ctx, &userGroups); err != nil {
+				return nil, fmt.Errorf("failed to decode groups: %w", err)
+			}
+			for _, group := range userGroups {
+				groups = append(groups, group.Name)
+			}
+		}
+		claims["groups"] = groups
+	}
+
+	return claims, nil
+}
+
+// IssueOIDCTokens creates the id token and the userinfo access token for an exchanged code
+func (s *UserService) IssueOIDCTokens(ctx context.Context, code *models.AuthorizationCode) (*models.OIDCTokenResponse, error) {
+	claims, err := s.OIDCUserClaims(ctx, code.UserID.Hex(), code.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := utils.CreateJWTToken(&utils.UserClaim{
+		UserID:   code.UserID.Hex(),
+		ClientID: code.ClientID,
+		Scope:    code.Scope,
+	}, utils.OIDCAccessTokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	lifetime := utils.TokenLifetime(utils.OIDCAccessTokenType)
+	now := time.Now()
+
+	// at_hash lets the client check the access token came with this id token
+	accessHash := sha256.Sum256([]byte(accessToken))
+
+	idClaims := jwt.MapClaims{
+		"iss":       utils.OIDCIssuer(),
+		"aud":       code.ClientID,
+		"iat":       now.Unix(),
+		"exp":       now.Add(lifetime).Unix(),
+		"auth_time": code.AuthTime.Unix(),
+		"at_hash":   base64.RawURLEncoding.EncodeToString(accessHash[:len(accessHash)/2]),
+	}
+	if code.Nonce != "" {
+		idClaims["nonce"] = code.Nonce
+	}
+	for key, value := range claims {
+		idClaims[key] = value
+	}
+
+	idToken, err := utils.SignIDToken(idClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OIDCTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(lifetime.Seconds()),
+		IDToken:     idToken,
+		Scope:       code.Scope,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/bushubdegefu/m-playground/mailer"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrPasswordResetInvalid = errors.New("the password reset link is invalid or has expired")
+
+// ##########################################################
+// ##########  Password Reset Services
+// ##########################################################
+
+func passwordResetState(user *models.User) utils.PasswordResetState {
+	return utils.PasswordResetState{
+		UserID:    user.ID.Hex(),
+		Password:  user.Password,
+		Email:     user.Email,
+		LastLogin: user.LastLogin,
+	}
+}
+
+// RequestPasswordReset mails a reset link to every active user with the email and a local password,
+// unknown emails are silently ignored so callers can not tell them apart
+func (s *UserService) RequestPasswordReset(ctx context.Context, email string) error {
+	cursor, err := s.Collection.Find(ctx, notDeleted(bson.M{"email": email, "is_active": true}),
+		options.Find().SetCollation(caseInsensitiveCollation))
+	if err != nil {
+		return fmt.Errorf("failed to fetch users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return fmt.Errorf("failed to decode users: %w", err)
+	}
+
+	resetURL := configs.AppConfig.GetOrDefault("PASSWORD_RESET_URL", "http://localhost:3000/reset-password")
+	for _, user := range users {
+		// passwords of users from external backends like LDAP are managed there
+		if !utils.HasUsablePassword(user.Password) {
+			continue
+		}
+
+		token, err := utils.MakePasswordResetToken(passwordResetState(&user))
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		query.Set("uid", user.ID.Hex())
+		query.Set("token", token)
+
+		err = AppMailer.Send(ctx, mailer.Message{
+			To:      []string{user.Email},
+			Subject: "Password reset",
+			Body: fmt.Sprintf("Hello %s,\n\nA password reset was requested for your account. "+
+				"Use the link below to choose a new password:\n\n%s?%s\n\n"+
+				"If you did not request this you can ignore this email.\n",
+				user.Username, resetURL, query.Encode()),
+		})
+		if err != nil {
+			log.Printf("password reset mail to user %s failed: %v", user.ID.Hex(), err)
+		}
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password when the token is valid, the token stops working once used
+// since it is bound to the old password hash
+func (s *UserService) ResetPassword(ctx context.Context, userID string, token string, newPassword string) error {
+	user, err := s.findUser(ctx, userID)
+	if err != nil || !user.IsActive || !utils.HasUsablePassword(user.Password) {
+		return ErrPasswordResetInvalid
+	}
+
+	if !utils.CheckPasswordResetToken(passwordResetState(user), token) {
+		return ErrPasswordResetInvalid
+	}
+
+	err = utils.ValidatePassword(newPassword, passwordUserAttributes(user.Username, user.FirstName, user.LastName, user.Email))
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := models.HashFunc(newPassword)
+	if err != nil {
+		return err
+	}
+
+	// matching the old hash so two requests with the same token can not both succeed
+	err = audited(ctx, s.Collection, models.ObjectTypeUser, models.AuditActionUpdate, user.ID, func(sc mongo.SessionContext) error {
+		result, err := s.Collection.UpdateOne(sc, bson.M{"_id": user.ID, "password": user.Password}, bson.M{
+			"$set": bson.M{"password": hashedPassword, "updated_at": time.Now()},
+			"$inc": incrementVersion,
+		})
+		if err != nil {
+			return fmt.Errorf("update failed: %w", err)
+		}
+		if result.ModifiedCount == 0 {
+			return ErrPasswordResetInvalid
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Removing Cache since password changed
+	AppCacheService.Delete("user:" + userID)
+
+	// signing out everywhere, whoever knew the old password should not stay logged in
+	if err := HandlerRefreshTokenService.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+	return HandlerSessionService.RevokeUserSessions(ctx, userID)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/bushubdegefu/m-playground/django-auth/models"
@@ -35,40 +38,37 @@ func NewPermissionService(client *mongo.Client) (*PermissionService, error) {
 	return &HandlerPermissionService, nil
 }
 
-// Utility function for transactions
-func (s *PermissionService) withTransaction(ctx context.Context, fn func(sc mongo.SessionContext) error) error {
-	session, err := s.Client.StartSession()
-	if err != nil {
-		return fmt.Errorf("start session failed: %w", err)
-	}
-	defer session.EndSession(ctx)
-
-	return mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
-		if err := session.StartTransaction(); err != nil {
-			return err
-		}
-		if err := fn(sc); err != nil {
-			session.AbortTransaction(sc)
-			return err
-		}
-		return session.CommitTransaction(sc)
+// EnsureIndexes makes codenames unique, documents still holding the old date typed codename are left out
+func (s *PermissionService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "codename", Value: 1}, {Key: "deleted_at", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"codename": bson.M{"$type": "string"}}),
 	})
+	return err
 }
 
 // Create inserts a new permission
 func (s *PermissionService) Create(ctx context.Context, posted_permission *models.PermissionPost) (*models.PermissionGet, error) {
 	var createdPermission = new(models.PermissionGet)
 
-	err := s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+	permissionID := primitive.NewObjectID()
+	err := audited(ctx, s.Collection, models.ObjectTypePermission, models.AuditActionCreate, permissionID, func(sc mongo.SessionContext) error {
 
 		permission := models.Permission{
-			ID:        primitive.NewObjectID(),
+			ID:        permissionID,
 			Name:      posted_permission.Name,
+			Codename:  posted_permission.Codename,
+			AppLabel:  posted_permission.AppLabel,
+			Version:   1,
 			CreatedAt: time.Now(),
 		}
 
-		_, err := s.Collection.InsertOne(ctx, permission)
+		_, err := s.Collection.InsertOne(sc, permission)
 		if err != nil {
+			if conflict := uniqueConflict(err); conflict != nil {
+				return conflict
+			}
 			return fmt.Errorf("insert failed: %w", err)
 		}
 
@@ -98,7 +98,7 @@ func (s *PermissionService) GetOne(ctx context.Context, id string) (*models.Perm
 	}
 
 	var permission models.PermissionGet
-	err = s.Collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&permission)
+	err = s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&permission)
 	if err != nil {
 		return nil, err
 	}
@@ -109,11 +109,31 @@ func (s *PermissionService) GetOne(ctx context.Context, id string) (*models.Perm
 	return &permission, nil
 }
 
+// StoredCodenames returns those of the codenames that exist as permissions outside the trash
+func (s *PermissionService) StoredCodenames(ctx context.Context, codenames []string) ([]string, error) {
+	cursor, err := s.Collection.Find(ctx, notDeleted(bson.M{"codename": bson.M{"$in": codenames}}),
+		options.Find().SetProjection(bson.M{"codename": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch permissions: %w", err)
+	}
+
+	var permissions []models.Permission
+	if err := cursor.All(ctx, &permissions); err != nil {
+		return nil, fmt.Errorf("failed to decode permissions: %w", err)
+	}
+
+	stored := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		stored = append(stored, permission.Codename)
+	}
+	return stored, nil
+}
+
 // Get returns permissions with pagination and search
 func (s *PermissionService) Get(ctx context.Context, pagination models.Pagination, searchFields []string, searchTerm []string) ([]models.PermissionGet, uint, error) {
 
 	// Build search query if any
-	filter := bson.M{}
+	filter := notDeleted(bson.M{})
 	if len(searchTerm) > 0 && len(searchFields) > 0 && len(searchFields) >= len(searchTerm) {
 		var orConditions []bson.M
 		for index, term := range searchTerm {
@@ -152,70 +172,289 @@ func (s *PermissionService) Get(ctx context.Context, pagination models.Paginatio
 }
 
 // Update modifies a Permissions by ID
-func (s *PermissionService) Update(ctx context.Context, patch_permission *models.PermissionPatch, id string) (*models.PermissionGet, error) {
+func (s *PermissionService) Update(ctx context.Context, patch_permission *models.PermissionPatch, id string, expectedVersion *int64) (*models.PermissionGet, error) {
 	// update User
-	var updatedPermission *models.PermissionGet
+	var updatedPermission = new(models.PermissionGet)
 
 	permission_id, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return &models.PermissionGet{}, fmt.Errorf("invalid ID: %w", err)
 	}
 
-	err = s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+	err = audited(ctx, s.Collection, models.ObjectTypePermission, models.AuditActionUpdate, permission_id, func(sc mongo.SessionContext) error {
 		updateFields := bson.M{}
 		if patch_permission.Name != nil {
 			updateFields["name"] = *patch_permission.Name
 		}
+		if patch_permission.Codename != nil {
+			updateFields["codename"] = *patch_permission.Codename
+		}
+		if patch_permission.AppLabel != nil {
+			updateFields["app_label"] = *patch_permission.AppLabel
+		}
 		updateFields["updated_at"] = time.Now()
 
 		// filter to use to update value by
-		filterPermission := bson.M{"_id": permission_id}
-		updatePermission := bson.M{"$set": updateFields}
-		// Update the document by ID
-		_, err := s.Collection.UpdateOne(ctx, filterPermission, updatePermission)
+		filterPermission := matchVersion(notDeleted(bson.M{"_id": permission_id}), expectedVersion)
+		updatePermission := bson.M{"$set": updateFields, "$inc": incrementVersion}
+		// Update the document by ID, returning it as stored after the update
+		err := s.Collection.FindOneAndUpdate(sc, filterPermission, updatePermission,
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(updatedPermission)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return missingOrStale(sc, s.Collection, permission_id, expectedVersion, ErrPermissionNotFound)
+		}
 		if err != nil {
-			return fmt.Errorf("insert failed: %w", err)
+			if conflict := uniqueConflict(err); conflict != nil {
+				return conflict
+			}
+			return fmt.Errorf("update failed: %w", err)
 		}
 
-		// Removing Cache if update sucess
-		cacheKey := "permission:" + id
-		AppCacheService.Delete(cacheKey)
-
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Removing Cache once the update is committed, a read before the commit would cache the old version again
+	AppCacheService.Delete("permission:" + id)
+	invalidateEffectivePermissions()
+
+	return updatedPermission, nil
+}
 
-	copier.Copy(&updatedPermission, patch_permission)
-	return updatedPermission, err
+// Delete moves a permission to the trash, or removes it for good when SOFT_DELETE_ENABLED is false
+func (s *PermissionService) Delete(ctx context.Context, id string, deletedBy string, expectedVersion *int64) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %w", err)
+	}
+
+	if SoftDeleteEnabled() {
+		err = audited(ctx, s.Collection, models.ObjectTypePermission, models.AuditActionDelete, objID, func(sc mongo.SessionContext) error {
+			return softDelete(sc, s.Collection, objID, deletedBy, expectedVersion, ErrPermissionNotFound)
+		})
+	} else {
+		err = s.hardDelete(ctx, objID, time.Time{}, expectedVersion)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Removing Cache if delete sucess
+	cacheKey := "permission:" + id
+	AppCacheService.Delete(cacheKey)
+	invalidateEffectivePermissions()
+
+	return nil
 }
 
-// Delete removes a permission by ID
-func (s *PermissionService) Delete(ctx context.Context, id string) error {
+// hardDelete removes a permission for good, with trashedBefore set only when it went to the trash before then
+// and expectedVersion set only when it must still be that version
+func (s *PermissionService) hardDelete(ctx context.Context, objID primitive.ObjectID, trashedBefore time.Time, expectedVersion *int64) error {
+	filter := matchVersion(bson.M{"_id": objID}, expectedVersion)
+	if !trashedBefore.IsZero() {
+		filter["deleted_at"] = bson.M{"$lt": trashedBefore}
+	}
 
-	err := s.withTransaction(ctx, func(sc mongo.SessionContext) error {
-		objID, err := primitive.ObjectIDFromHex(id)
-		if err != nil {
-			return fmt.Errorf("invalid ID: %w", err)
-		}
+	// a delete without the trash or the scheduled purge of the trash
+	action := models.AuditActionDelete
+	if !trashedBefore.IsZero() {
+		action = models.AuditActionPurge
+	}
 
-		result, err := s.Collection.DeleteOne(ctx, bson.M{"_id": objID})
+	var users, groups []primitive.ObjectID
+	err := audited(ctx, s.Collection, models.ObjectTypePermission, action, objID, func(sc mongo.SessionContext) error {
+		result, err := s.Collection.DeleteOne(sc, filter)
 		if err != nil {
 			return err
 		}
 
 		if result.DeletedCount == 0 {
-			return errors.New("no document deleted")
+			return missingOrStale(sc, s.Collection, objID, expectedVersion, ErrPermissionNotFound)
 		}
 
-		// Removing Cache if delete sucess
-		cacheKey := "permission:" + id
-		AppCacheService.Delete(cacheKey)
+		// users and groups keep no reference to the deleted permission
+		users, err = relation{owners: HandlerUserService.Collection, entity: models.ObjectTypeUser, field: "permission_ids"}.detach(sc, objID)
+		if err != nil {
+			return err
+		}
+		groups, err = relation{owners: HandlerGroupService.Collection, entity: models.ObjectTypeGroup, field: "permission_ids"}.detach(sc, objID)
+		if err != nil {
+			return err
+		}
+		if err := HandlerObjectPermissionService.deleteReferencing(sc, "permission_id", models.ObjectTypePermission, objID); err != nil {
+			return err
+		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	AppCacheService.Delete("permission:" + objID.Hex())
+	for _, user := range users {
+		AppCacheService.Delete("user:" + user.Hex())
+	}
+	for _, group := range groups {
+		AppCacheService.Delete("group:" + group.Hex())
+	}
+	invalidateEffectivePermissions()
+	return nil
+}
+
+// Restore takes a permission out of the trash
+func (s *PermissionService) Restore(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrPermissionNotFound
+	}
+
+	err = audited(ctx, s.Collection, models.ObjectTypePermission, models.AuditActionRestore, objID, func(sc mongo.SessionContext) error {
+		return restore(sc, s.Collection, objID, ErrPermissionNotFound)
+	})
+	if err != nil {
+		return err
+	}
+
+	AppCacheService.Delete("permission:" + id)
+	invalidateEffectivePermissions()
+	return nil
+}
+
+// GetTrash returns the permissions in the trash with pagination, most recently deleted first
+func (s *PermissionService) GetTrash(ctx context.Context, pagination models.Pagination) ([]models.PermissionGet, uint, error) {
+	filter := inTrash()
+
+	//pagination logic
+	opts := options.Find().
+		SetSort(bson.D{{Key: "deleted_at", Value: -1}}).
+		SetSkip(int64(pagination.Page * pagination.Size)).
+		SetLimit(int64(pagination.Size))
+
+	totalCount, _ := s.Collection.CountDocuments(ctx, filter)
+
+	cursor, err := s.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, uint(totalCount), err
+	}
+	defer cursor.Close(ctx)
+
+	var permissions []models.PermissionGet
+	for cursor.Next(ctx) {
+		var item models.PermissionGet
+		if err := cursor.Decode(&item); err != nil {
+			return nil, uint(totalCount), err
+		}
+		permissions = append(permissions, item)
+	}
+
+	return permissions, uint(totalCount), nil
 }
 
 // ##########################################################
 // ##########  Custom Services Add Here   ###################
 // ##########################################################
+
+// MigrateCodenames gives permissions stored while the codename was a date their name as codename,
+// names that clash with an existing codename are reported and left for an admin to fix
+func (s *PermissionService) MigrateCodenames(ctx context.Context) error {
+	// only the name is decoded, the old codename does not fit the string field
+	cursor, err := s.Collection.Find(ctx, bson.M{"codename": bson.M{"$not": bson.M{"$type": "string"}}},
+		options.Find().SetProjection(bson.M{"_id": 1, "name": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to fetch permissions: %w", err)
+	}
+	var permissions []models.Permission
+	if err := cursor.All(ctx, &permissions); err != nil {
+		return fmt.Errorf("failed to decode permissions: %w", err)
+	}
+
+	for _, permission := range permissions {
+		err := audited(ctx, s.Collection, models.ObjectTypePermission, models.AuditActionUpdate, permission.ID, func(sc mongo.SessionContext) error {
+			_, err := s.Collection.UpdateOne(sc, bson.M{"_id": permission.ID}, bson.M{
+				"$set": bson.M{"codename": permission.Name, "updated_at": time.Now()},
+				"$inc": incrementVersion,
+			})
+			return err
+		})
+		if uniqueConflict(err) != nil {
+			log.Printf("permission %s keeps no codename, %q is already taken", permission.ID.Hex(), permission.Name)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("update failed: %w", err)
+		}
+		AppCacheService.Delete("permission:" + permission.ID.Hex())
+	}
+	invalidateEffectivePermissions()
+	return nil
+}
+
+// SyncRoutePermissions creates a permission for every codename that does not have one yet and sets the
+// app label, codenames maps each route name to its app label. Returns the number of permissions created.
+func (s *PermissionService) SyncRoutePermissions(ctx context.Context, codenames map[string]string) (int, error) {
+	names := make([]string, 0, len(codenames))
+	for codename := range codenames {
+		names = append(names, codename)
+	}
+	slices.Sort(names)
+
+	created := 0
+	for _, codename := range names {
+		appLabel := codenames[codename]
+
+		var existing models.Permission
+		// a permission in the trash does not count, the route gets a new one
+		err := s.Collection.FindOne(ctx, notDeleted(bson.M{"codename": codename})).Decode(&existing)
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments):
+			permission := models.Permission{
+				ID:        primitive.NewObjectID(),
+				Name:      permissionName(codename, appLabel),
+				Codename:  codename,
+				AppLabel:  appLabel,
+				Version:   1,
+				CreatedAt: time.Now(),
+			}
+			err = audited(ctx, s.Collection, models.ObjectTypePermission, models.AuditActionCreate, permission.ID, func(sc mongo.SessionContext) error {
+				_, err := s.Collection.InsertOne(sc, permission)
+				return err
+			})
+			if uniqueConflict(err) != nil {
+				// another instance created it at the same time
+				continue
+			}
+			if err != nil {
+				return created, fmt.Errorf("sync of %s failed: %w", codename, err)
+			}
+			created++
+		case err != nil:
+			return created, fmt.Errorf("sync of %s failed: %w", codename, err)
+		case existing.AppLabel != appLabel:
+			err = audited(ctx, s.Collection, models.ObjectTypePermission, models.AuditActionUpdate, existing.ID, func(sc mongo.SessionContext) error {
+				_, err := s.Collection.UpdateOne(sc, bson.M{"_id": existing.ID}, bson.M{
+					"$set": bson.M{"app_label": appLabel, "updated_at": time.Now()},
+					"$inc": incrementVersion,
+				})
+				return err
+			})
+			if err != nil {
+				return created, fmt.Errorf("sync of %s failed: %w", codename, err)
+			}
+			AppCacheService.Delete("permission:" + existing.ID.Hex())
+		}
+	}
+	invalidateEffectivePermissions()
+	return created, nil
+}
+
+// readable name of a route codename, django_auth_can_view_user becomes "Can view user"
+func permissionName(codename, appLabel string) string {
+	name := strings.ReplaceAll(strings.TrimPrefix(codename, appLabel+"_"), "_", " ")
+	if name == "" {
+		return codename
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/jinzhu/copier"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var HandlerPersonalAccessTokenService PersonalAccessTokenService
+
+// PersonalAccessTokenPrefix marks x-app-token values that are personal access tokens instead of JWTs
+const PersonalAccessTokenPrefix = "pat_"
+
+var ErrPersonalAccessTokenInvalid = errors.New("invalid, expired or revoked personal access token")
+
+// PersonalAccessTokenService wraps MongoDB logic for personal access tokens
+type PersonalAccessTokenService struct {
+	Collection *mongo.Collection
+	Client     *mongo.Client
+	Database   *mongo.Database
+}
+
+// Constructor For Client
+func NewPersonalAccessTokenService(client *mongo.Client) (*PersonalAccessTokenService, error) {
+	database := client.Database("django_auth")
+	collection := database.Collection("PersonalAccessTokens")
+	HandlerPersonalAccessTokenService = PersonalAccessTokenService{
+		Collection: collection,
+		Client:     client,
+		Database:   database,
+	}
+	return &HandlerPersonalAccessTokenService, nil
+}
+
+// EnsureIndexes creates the lookup indexes
+func (s *PersonalAccessTokenService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	})
+	return err
+}
+
+// only the sha256 of random tokens and secrets is stored, they are random so no salt is needed
+func hashRandomToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// UngrantableScope returns the first scope the user can not hand to a personal access token, empty when
+// all of them can be. Scopes only narrow down the user's own permissions, for superusers that is every stored permission
+func (s *PersonalAccessTokenService) UngrantableScope(ctx context.Context, userID string, scopes []string) (string, error) {
+	permissions, err := HandlerUserService.GetEffectivePermissions(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if slices.Contains(permissions, "superuser") {
+		if permissions, err = HandlerPermissionService.StoredCodenames(ctx, scopes); err != nil {
+			return "", err
+		}
+	}
+
+	for _, scope := range scopes {
+		if scope == "superuser" || AuthenticatedRouteNames[scope] || !slices.Contains(permissions, scope) {
+			return scope, nil
+		}
+	}
+	return "", nil
+}
+
+// Create issues a new token for the user and returns the raw token once
+func (s *PersonalAccessTokenService) Create(ctx context.Context, userID string, posted_token *models.PersonalAccessTokenPost) (*models.PersonalAccessTokenCreated, error) {
+	user_id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate token failed: %w", err)
+	}
+	rawToken := PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	token := models.PersonalAccessToken{
+		ID:        primitive.NewObjectID(),
+		UserID:    user_id,
+		Name:      posted_token.Name,
+		Prefix:    rawToken[:len(PersonalAccessTokenPrefix)+8],
+		TokenHash: hashRandomToken(rawToken),
+		Scopes:    posted_token.Scopes,
+		CreatedAt: time.Now(),
+	}
+	if posted_token.ExpiresAt != nil {
+		token.ExpiresAt = *posted_token.ExpiresAt
+	}
+
+	if _, err := s.Collection.InsertOne(ctx, token); err != nil {
+		return nil, fmt.Errorf("insert failed: %w", err)
+	}
+
+	createdToken := &models.PersonalAccessTokenCreated{Token: rawToken}
+	if err := copier.Copy(&createdToken.PersonalAccessTokenGet, token); err != nil {
+		return nil, err
+	}
+	return createdToken, nil
+}
+
+// Authenticate resolves a raw token to its record and stamps the last used time
+func (s *PersonalAccessTokenService) Authenticate(ctx context.Context, rawToken string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	err := s.Collection.FindOne(ctx, bson.M{"token_hash": hashRandomToken(rawToken)}).Decode(&token)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrPersonalAccessTokenInvalid
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !token.RevokedAt.IsZero() || (!token.ExpiresAt.IsZero() && now.After(token.ExpiresAt)) {
+		return nil, ErrPersonalAccessTokenInvalid
+	}
+
+	// writing last used at most once a minute per token
+	_, err = s.Collection.UpdateOne(ctx, bson.M{
+		"_id": token.ID,
+		"$or": bson.A{
+			bson.M{"last_used_at": bson.M{"$exists": false}},
+			bson.M{"last_used_at": bson.M{"$lt": now.Add(-time.Minute)}},
+		},
+	}, bson.M{"$set": bson.M{"last_used_at": now}})
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// GetForUser lists every token of the user
+func (s *PersonalAccessTokenService) GetForUser(ctx context.Context, userID string) ([]models.PersonalAccessTokenGet, error) {
+	user_id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	cursor, err := s.Collection.Find(ctx, bson.M{"user_id": user_id}, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tokens: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	tokens := []models.PersonalAccessTokenGet{}
+	for cursor.Next(ctx) {
+		var t models.PersonalAccessTokenGet
+		if err := cursor.Decode(&t); err != nil {
+			return nil, fmt.Errorf("failed to decode token: %w", err)
+		}
+		tokens = append(tokens, t)
+	}
+
+	return tokens, nil
+}
+
+// Get returns tokens of every user with pagination, optionally for one user
+func (s *PersonalAccessTokenService) Get(ctx context.Context, pagination models.Pagination, userID string) ([]models.PersonalAccessTokenGet, uint, error) {
+	filter := bson.M{}
+	if userID != "" {
+		user_id, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid user ID: %w", err)
+		}
+		filter["user_id"] = user_id
+	}
+
+	//pagination logic
+	opts := options.Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetSkip(int64(pagination.Page * pagination.Size)).
+		SetLimit(int64(pagination.Size))
+
+	totalCount, _ := s.Collection.CountDocuments(ctx, filter)
+
+	cursor, err := s.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, uint(totalCount), err
+	}
+	defer cursor.Close(ctx)
+
+	var tokens []models.PersonalAccessTokenGet
+	for cursor.Next(ctx) {
+		var t models.PersonalAccessTokenGet
+		if err := cursor.Decode(&t); err != nil {
+			return nil, uint(totalCount), err
+		}
+		tokens = append(tokens, t)
+	}
+
+	return tokens, uint(totalCount), nil
+}
+
+// Revoke revokes a token, limited to the owner's tokens when userID is given
+func (s *PersonalAccessTokenService) Revoke(ctx context.Context, tokenID string, userID string) error {
+	token_id, err := primitive.ObjectIDFromHex(tokenID)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %w", err)
+	}
+
+	filter := bson.M{"_id": token_id, "revoked_at": bson.M{"$exists": false}}
+	if userID != "" {
+		user_id, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			return fmt.Errorf("invalid user ID: %w", err)
+		}
+		filter["user_id"] = user_id
+	}
+
+	result, err := s.Collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"revoked_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("no active token found")
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var HandlerRefreshTokenService RefreshTokenService
+
+var (
+	ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, session revoked")
+)
+
+// RefreshTokenService wraps MongoDB logic for refresh token families
+type RefreshTokenService struct {
+	Collection *mongo.Collection
+	Client     *mongo.Client
+	Database   *mongo.Database
+}
+
+// Constructor For Client
+func NewRefreshTokenService(client *mongo.Client) (*RefreshTokenService, error) {
+	database := client.Database("django_auth")
+	collection := database.Collection("RefreshTokens")
+	HandlerRefreshTokenService = RefreshTokenService{
+		Collection: collection,
+		Client:     client,
+		Database:   database,
+	}
+	return &HandlerRefreshTokenService, nil
+}
+
+// Utility function for transactions
+func (s *RefreshTokenService) withTransaction(ctx context.Context, fn func(sc mongo.SessionContext) error) error {
+	session, err := s.Client.StartSession()
+	if err != nil {
+		return fmt.Errorf("start session failed: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	return mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
+		if err := session.StartTransaction(); err != nil {
+			return err
+		}
+		if err := fn(sc); err != nil {
+			session.AbortTransaction(sc)
+			return err
+		}
+		return session.CommitTransaction(sc)
+	})
+}
+
+// EnsureIndexes creates the lookup indexes and lets mongo drop expired tokens
+func (s *RefreshTokenService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "jti", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "family_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "impersonator_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+// Create stores the first refresh token of a new family
+func (s *RefreshTokenService) Create(ctx context.Context, token *models.RefreshToken) error {
+	token.ID = primitive.NewObjectID()
+	token.CreatedAt = time.Now()
+	_, err := s.Collection.InsertOne(ctx, token)
+	if err != nil {
+		return fmt.Errorf("insert failed: %w", err)
+	}
+	return nil
+}
+
+// Rotate marks the presented refresh token as used and stores its successor
+// presenting an already used token revokes the whole family
+func (s *RefreshTokenService) Rotate(ctx context.Context, jti string, next *models.RefreshToken) error {
+	var reused *models.RefreshToken
+
+	err := s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		now := time.Now()
+
+		// only an unused token can be swapped, checked atomically in the filter
+		var current models.RefreshToken
+		err := s.Collection.FindOneAndUpdate(sc,
+			bson.M{"jti": jti, "revoked_at": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"revoked_at": now, "replaced_by": next.JTI}},
+		).Decode(&current)
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				return err
+			}
+
+			// either unknown, logged out or already used
+			var used models.RefreshToken
+			if err := s.Collection.FindOne(sc, bson.M{"jti": jti}).Decode(&used); err != nil || used.ReplacedBy == "" {
+				return ErrRefreshTokenInvalid
+			}
+			reused = &used
+			return ErrRefreshTokenReused
+		}
+
+		if current.UserID != next.UserID {
+			return ErrRefreshTokenInvalid
+		}
+
+		next.ID = primitive.NewObjectID()
+		next.FamilyID = current.FamilyID
+		next.CreatedAt = now
+		if _, err := s.Collection.InsertOne(sc, next); err != nil {
+			return fmt.Errorf("insert failed: %w", err)
+		}
+		return nil
+	})
+
+	// revoking outside the aborted transaction so the revocation sticks
+	if reused != nil {
+		log.Printf("refresh token reuse detected: jti %s family %s user %s", jti, reused.FamilyID, reused.UserID.Hex())
+		if rerr := s.RevokeFamily(ctx, reused.FamilyID); rerr != nil {
+			log.Printf("revoking token family %s failed: %v", reused.FamilyID, rerr)
+		}
+	}
+
+	return err
+}
+
+// RevokeFamily revokes every token issued from the same login and denylists their live access tokens
+func (s *RefreshTokenService) RevokeFamily(ctx context.Context, familyID string) error {
+	return s.revokeWhere(ctx, bson.M{"family_id": familyID})
+}
+
+// RevokeSession logs out the session the access token belongs to
+func (s *RefreshTokenService) RevokeSession(ctx context.Context, accessJTI string) error {
+	var token models.RefreshToken
+	err := s.Collection.FindOne(ctx, bson.M{"access_jti": accessJTI}).Decode(&token)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		return err
+	}
+	return s.RevokeFamily(ctx, token.FamilyID)
+}
+
+// RevokeUserSessions revokes every session of the user and the impersonations the user started
+func (s *RefreshTokenService) RevokeUserSessions(ctx context.Context, userID string) error {
+	user_id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+	return s.revokeWhere(ctx, bson.M{"$or": bson.A{
+		bson.M{"user_id": user_id},
+		bson.M{"impersonator_id": user_id},
+	}})
+}
+
+// revokes matching refresh tokens and denylists access tokens that have not expired yet
+func (s *RefreshTokenService) revokeWhere(ctx context.Context, filter bson.M) error {
+	now := time.Now()
+
+	liveFilter := bson.M{"access_expires_at": bson.M{"$gt": now}}
+	for key, value := range filter {
+		liveFilter[key] = value
+	}
+
+	cursor, err := s.Collection.Find(ctx, liveFilter)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tokens: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var token models.RefreshToken
+		if err := cursor.Decode(&token); err != nil {
+			return fmt.Errorf("failed to decode token: %w", err)
+		}
+		if err := HandlerRevokedTokenService.Revoke(ctx, token.AccessJTI, token.UserID, token.AccessExpiresAt); err != nil {
+			return err
+		}
+	}
+
+	filter["revoked_at"] = bson.M{"$exists": false}
+	_, err = s.Collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"revoked_at": now}})
+	return err
+}
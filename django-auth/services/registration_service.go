@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/bushubdegefu/m-playground/mailer"
+	"github.com/jinzhu/copier"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrRegistrationDisabled     = errors.New("registration is disabled")
+	ErrEmailDomainNotAllowed    = errors.New("registration is not open for this email domain")
+	ErrEmailVerificationInvalid = errors.New("the verification link is invalid or has expired")
+)
+
+// ##########################################################
+// ##########  Registration Services
+// ##########################################################
+
+func emailVerificationState(user *models.User) utils.EmailVerificationState {
+	return utils.EmailVerificationState{
+		UserID:              user.ID.Hex(),
+		Email:               user.Email,
+		PendingVerification: user.PendingVerification,
+	}
+}
+
+// RegistrationEnabled is REGISTRATION_ENABLED, sign up is off unless turned on
+func RegistrationEnabled() bool {
+	return configs.AppConfig.GetOrDefault("REGISTRATION_ENABLED", "false") == "true"
+}
+
+// comma separated setting as a trimmed list without empty entries
+func configList(key string) []string {
+	values := []string{}
+	for _, value := range strings.Split(configs.AppConfig.GetOrDefault(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// REGISTRATION_ALLOWED_DOMAINS limits sign up to those email domains, empty allows every domain
+func registrationDomainAllowed(email string) bool {
+	allowed := configList("REGISTRATION_ALLOWED_DOMAINS")
+	if len(allowed) == 0 {
+		return true
+	}
+
+	_, domain, found := strings.Cut(email, "@")
+	if !found {
+		return false
+	}
+	return slices.ContainsFunc(allowed, func(allowedDomain string) bool {
+		return strings.EqualFold(allowedDomain, domain)
+	})
+}
+
+// Register creates an inactive user that is activated by the emailed verification link,
+// new users join the groups named in REGISTRATION_DEFAULT_GROUPS
+func (s *UserService) Register(ctx context.Context, posted *models.RegistrationPost) (*models.User, error) {
+	if !RegistrationEnabled() {
+		return nil, ErrRegistrationDisabled
+	}
+
+	if !registrationDomainAllowed(posted.Email) {
+		return nil, ErrEmailDomainNotAllowed
+	}
+
+	err := utils.ValidatePassword(posted.Password, passwordUserAttributes(posted.Username, posted.FirstName, posted.LastName, posted.Email))
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.Collection.CountDocuments(ctx, notDeleted(bson.M{"username": posted.Username}),
+		options.Count().SetCollation(caseInsensitiveCollation))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check username: %w", err)
+	}
+	if count > 0 {
+		return nil, ErrUsernameTaken
+	}
+
+	groupIDs := []primitive.ObjectID{}
+	if names := configList("REGISTRATION_DEFAULT_GROUPS"); len(names) > 0 {
+		cursor, err := HandlerGroupService.Collection.Find(ctx, notDeleted(bson.M{"name": bson.M{"$in": names}}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch groups: %w", err)
+		}
+		var groups []models.Group
+		if err := cursor.All(ctx, &groups); err != nil {
+			return nil, fmt.Errorf("failed to decode groups: %w", err)
+		}
+		for _, group := range groups {
+			groupIDs = append(groupIDs, group.ID)
+		}
+		if len(groups) != len(names) {
+			log.Printf("some of the registration default groups %v do not exist", names)
+		}
+	}
+
+	hashedPassword, err := models.HashFunc(posted.Password)
+	if err != nil {
+		return nil, fmt.Errorf("hashing password failed: %w", err)
+	}
+
+	user := models.User{
+		ID:                  primitive.NewObjectID(),
+		Password:            hashedPassword,
+		Username:            posted.Username,
+		FirstName:           posted.FirstName,
+		LastName:            posted.LastName,
+		Email:               posted.Email,
+		IsActive:            false,
+		GroupIDs:            groupIDs,
+		PendingVerification: true,
+		Version:             1,
+		CreatedAt:           time.Now(),
+	}
+
+	err = audited(ctx, s.Collection, models.ObjectTypeUser, models.AuditActionCreate, user.ID, func(sc mongo.SessionContext) error {
+		if _, err := s.Collection.InsertOne(sc, user); err != nil {
+			if conflict := uniqueConflict(err); conflict != nil {
+				return conflict
+			}
+			return fmt.Errorf("insert failed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SendVerificationEmail mails the link that activates the account
+func (s *UserService) SendVerificationEmail(ctx context.Context, user *models.User) error {
+	token, err := utils.MakeEmailVerificationToken(emailVerificationState(user))
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("uid", user.ID.Hex())
+	query.Set("token", token)
+
+	verifyURL := configs.AppConfig.GetOrDefault("EMAIL_VERIFICATION_URL", "http://localhost:3000/verify-email")
+	return AppMailer.Send(ctx, mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease confirm your email address to activate your account:\n\n%s?%s\n\n"+
+			"If you did not sign up you can ignore this email.\n",
+			user.Username, verifyURL, query.Encode()),
+	})
+}
+
+// SendAccountExistsEmail tells the owner of an email that signing up again is not needed,
+// the sign up itself answers like a new account so it can not be used to find registered emails
+func (s *UserService) SendAccountExistsEmail(ctx context.Context, email string) error {
+	resetURL := configs.AppConfig.GetOrDefault("PASSWORD_RESET_URL", "http://localhost:3000/reset-password")
+	return AppMailer.Send(ctx, mailer.Message{
+		To:      []string{email},
+		Subject: "You already have an account",
+		Body: fmt.Sprintf("Hello,\n\nSomeone tried to sign up with this email address, but it already belongs to an account.\n\n"+
+			"If it was you, sign in or reset your password at:\n\n%s\n\nIf it was not you, you can ignore this email.\n",
+			resetURL),
+	})
+}
+
+// ResendVerification mails a new link to every account with the email still waiting for verification,
+// unknown emails are silently ignored so callers can not tell them apart
+func (s *UserService) ResendVerification(ctx context.Context, email string) error {
+	cursor, err := s.Collection.Find(ctx, notDeleted(bson.M{"email": email, "pending_verification": true, "is_active": false}),
+		options.Find().SetCollation(caseInsensitiveCollation))
+	if err != nil {
+		return fmt.Errorf("failed to fetch users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return fmt.Errorf("failed to decode users: %w", err)
+	}
+
+	for _, user := range users {
+		if err := s.SendVerificationEmail(ctx, &user); err != nil {
+			log.Printf("verification mail to user %s failed: %v", user.ID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+// VerifyEmail activates the account when the token is valid, the token stops working once used
+func (s *UserService) VerifyEmail(ctx context.Context, userID string, token string) (*models.UserGet, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil || !user.PendingVerification {
+		return nil, ErrEmailVerificationInvalid
+	}
+
+	if !utils.CheckEmailVerificationToken(emailVerificationState(user), token) {
+		return nil, ErrEmailVerificationInvalid
+	}
+
+	// matching the pending state and email so a used or outdated link can not activate again
+	now := time.Now()
+	err = audited(ctx, s.Collection, models.ObjectTypeUser, models.AuditActionUpdate, user.ID, func(sc mongo.SessionContext) error {
+		result, err := s.Collection.UpdateOne(sc, bson.M{"_id": user.ID, "pending_verification": true, "email": user.Email}, bson.M{
+			"$set":   bson.M{"is_active": true, "email_verified_at": now, "updated_at": now},
+			"$unset": bson.M{"pending_verification": ""},
+			"$inc":   incrementVersion,
+		})
+		if err != nil {
+			return fmt.Errorf("update failed: %w", err)
+		}
+		if result.ModifiedCount == 0 {
+			return ErrEmailVerificationInvalid
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Removing Cache since the user was activated
+	AppCacheService.Delete("user:" + userID)
+	invalidateEffectivePermissions()
+
+	user.IsActive = true
+	user.PendingVerification = false
+	user.EmailVerifiedAt = now
+
+	verifiedUser := new(models.UserGet)
+	if err := copier.Copy(verifiedUser, user); err != nil {
+		return nil, err
+	}
+	return verifiedUser, nil
+}
+
+// PurgeUnverifiedUsers deletes sign ups that were not verified within REGISTRATION_EXPIRE_DAYS
+// so their usernames become available again
+func (s *UserService) PurgeUnverifiedUsers(ctx context.Context) (int64, error) {
+	days, err := strconv.Atoi(configs.AppConfig.GetOrDefault("REGISTRATION_EXPIRE_DAYS", "7"))
+	if err != nil || days <= 0 {
+		days = 7
+	}
+
+	filter := bson.M{
+		"pending_verification": true,
+		"is_active":            false,
+		"created_at":           bson.M{"$lt": time.Now().AddDate(0, 0, -days)},
+	}
+	ids, err := existingIDs(ctx, s.Collection, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	// one by one so every removed sign up gets its audit entry
+	var purged int64
+	for id := range ids {
+		err := audited(ctx, s.Collection, models.ObjectTypeUser, models.AuditActionPurge, id, func(sc mongo.SessionContext) error {
+			filter["_id"] = id
+			result, err := s.Collection.DeleteOne(sc, filter)
+			if err != nil {
+				return fmt.Errorf("delete failed: %w", err)
+			}
+			if result.DeletedCount == 0 {
+				// verified in the meantime
+				return mongo.ErrNoDocuments
+			}
+			return nil
+		})
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var HandlerRevokedTokenService RevokedTokenService
+
+// RevokedTokenService wraps the token denylist, cache first with mongo as the source of truth
+type RevokedTokenService struct {
+	Collection *mongo.Collection
+	Client     *mongo.Client
+	Database   *mongo.Database
+}
+
+// Constructor For Client
+func NewRevokedTokenService(client *mongo.Client) (*RevokedTokenService, error) {
+	database := client.Database("django_auth")
+	collection := database.Collection("RevokedTokens")
+	HandlerRevokedTokenService = RevokedTokenService{
+		Collection: collection,
+		Client:     client,
+		Database:   database,
+	}
+	return &HandlerRevokedTokenService, nil
+}
+
+// EnsureIndexes creates the jti index and lets mongo drop entries once the token expired
+func (s *RevokedTokenService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "jti", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+// Revoke puts the token id on the denylist until the token expires
+func (s *RevokedTokenService) Revoke(ctx context.Context, jti string, userID primitive.ObjectID, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if jti == "" || ttl <= 0 {
+		// nothing to do for tokens that are already expired
+		return nil
+	}
+
+	_, err := s.Collection.UpdateOne(ctx,
+		bson.M{"jti": jti},
+		bson.M{"$setOnInsert": models.RevokedToken{
+			ID:        primitive.NewObjectID(),
+			JTI:       jti,
+			UserID:    userID,
+			ExpiresAt: expiresAt,
+			CreatedAt: time.Now(),
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("revoke token failed: %w", err)
+	}
+
+	AppCacheService.SetWithTTL("revoked_token:"+jti, true, ttl)
+	return nil
+}
+
+// IsRevoked checks the cache and falls back to mongo so a restart keeps revoked tokens revoked
+func (s *RevokedTokenService) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	cacheKey := "revoked_token:" + jti
+	if _, found := AppCacheService.Get(cacheKey); found {
+		return true, nil
+	}
+
+	var revoked models.RevokedToken
+	err := s.Collection.FindOne(ctx, bson.M{"jti": jti}).Decode(&revoked)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// warming the cache for the next request
+	if ttl := time.Until(revoked.ExpiresAt); ttl > 0 {
+		AppCacheService.SetWithTTL(cacheKey, true, ttl)
+	}
+	return true, nil
+}
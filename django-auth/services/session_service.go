@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var HandlerSessionService SessionService
+
+var ErrSessionInvalid = errors.New("invalid or expired session")
+
+// CSRFHeaderName carries the csrf token of a cookie session on unsafe requests,
+// CSRFCookieName holds the same token where the frontend can read it
+const (
+	CSRFHeaderName = "X-CSRF-Token"
+	CSRFCookieName = "csrf_token"
+)
+
+// SessionStore persists cookie sessions, picked with SESSION_STORE (mongo or cache)
+type SessionStore interface {
+	Get(ctx context.Context, keyHash string) (*models.Session, error)
+	Save(ctx context.Context, session *models.Session) error
+	Touch(ctx context.Context, session *models.Session, seenAt time.Time) error
+	Delete(ctx context.Context, keyHash string) error
+	DeleteUser(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// SessionService manages server side sessions of browser clients
+type SessionService struct {
+	Collection *mongo.Collection
+	Client     *mongo.Client
+	Database   *mongo.Database
+	Store      SessionStore
+}
+
+// Constructor For Client
+func NewSessionService(client *mongo.Client) (*SessionService, error) {
+	database := client.Database("django_auth")
+	collection := database.Collection("Sessions")
+
+	var store SessionStore
+	switch kind := configs.AppConfig.GetOrDefault("SESSION_STORE", "mongo"); kind {
+	case "mongo":
+		store = &mongoSessionStore{collection: collection}
+	case "cache":
+		// sessions only live in the memory of this instance, use it for single instance setups
+		store = &cacheSessionStore{revocations: database.Collection("SessionRevocations")}
+	default:
+		return nil, fmt.Errorf("unknown session store: %s", kind)
+	}
+
+	HandlerSessionService = SessionService{
+		Collection: collection,
+		Client:     client,
+		Database:   database,
+		Store:      store,
+	}
+	return &HandlerSessionService, nil
+}
+
+// EnsureIndexes creates the lookup and expiry indexes when sessions are kept in mongo,
+// with the cache store only the revocations are in mongo and expire with the sessions they cut off
+func (s *SessionService) EnsureIndexes(ctx context.Context) error {
+	if store, ok := s.Store.(*cacheSessionStore); ok {
+		_, err := store.revocations.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		})
+		return err
+	}
+	_, err := s.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+// SessionCookieName is the name of the HttpOnly cookie holding the session key
+func SessionCookieName() string {
+	return configs.AppConfig.GetOrDefault("SESSION_COOKIE_NAME", "django_auth_session")
+}
+
+// absolute lifetime of a session and how long it survives without requests
+func sessionLifetimes() (time.Duration, time.Duration) {
+	maxMinutes, err := strconv.Atoi(configs.AppConfig.GetOrDefault("SESSION_MAX_MINUTES", "720"))
+	if err != nil || maxMinutes <= 0 {
+		maxMinutes = 720
+	}
+	idleMinutes, err := strconv.Atoi(configs.AppConfig.GetOrDefault("SESSION_IDLE_MINUTES", "60"))
+	if err != nil || idleMinutes <= 0 {
+		idleMinutes = 60
+	}
+	return time.Duration(maxMinutes) * time.Minute, time.Duration(idleMinutes) * time.Minute
+}
+
+func newSessionSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("generate session secret failed: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// Create starts a session for the user and returns the raw key to set as cookie value
+func (s *SessionService) Create(ctx context.Context, userID string, ip string, userAgent string) (string, *models.Session, error) {
+	user_id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	key, err := newSessionSecret()
+	if err != nil {
+		return "", nil, err
+	}
+	csrfToken, err := newSessionSecret()
+	if err != nil {
+		return "", nil, err
+	}
+
+	maxLifetime, _ := sessionLifetimes()
+	now := time.Now()
+	session := &models.Session{
+		ID:         primitive.NewObjectID(),
+		KeyHash:    hashRandomToken(key),
+		UserID:     user_id,
+		CSRFToken:  csrfToken,
+		IP:         ip,
+		UserAgent:  userAgent,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(maxLifetime),
+		CreatedAt:  now,
+	}
+
+	if err := s.Store.Save(ctx, session); err != nil {
+		return "", nil, err
+	}
+	return key, session, nil
+}
+
+// Authenticate resolves the cookie value to its session, sessions idle for too long are ended
+func (s *SessionService) Authenticate(ctx context.Context, key string) (*models.Session, error) {
+	if key == "" {
+		return nil, ErrSessionInvalid
+	}
+
+	session, err := s.Store.Get(ctx, hashRandomToken(key))
+	if err != nil {
+		return nil, err
+	}
+
+	_, idleTimeout := sessionLifetimes()
+	now := time.Now()
+	if now.After(session.ExpiresAt) || now.After(session.LastSeenAt.Add(idleTimeout)) {
+		if err := s.Store.Delete(ctx, session.KeyHash); err != nil {
+			return nil, err
+		}
+		return nil, ErrSessionInvalid
+	}
+
+	// writing last seen at most once a minute per session
+	if now.Sub(session.LastSeenAt) > time.Minute {
+		if err := s.Store.Touch(ctx, session, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return session, nil
+}
+
+// UserClaim builds the request claim of the session, users deactivated since login are refused
+func (s *SessionService) UserClaim(ctx context.Context, session *models.Session) (*utils.UserClaim, error) {
+	user, err := HandlerUserService.GetOne(ctx, session.UserID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrSessionInvalid
+	}
+
+	claim := &utils.UserClaim{
+		UserID:      user.ID.Hex(),
+		Username:    user.Username,
+		Email:       user.Email,
+		IsSuperuser: user.IsSuperuser,
+		TokenType:   utils.SessionTokenType,
+	}
+	claim.ID = session.ID.Hex()
+	return claim, nil
+}
+
+// Revoke ends the session
+func (s *SessionService) Revoke(ctx context.Context, session *models.Session) error {
+	return s.Store.Delete(ctx, session.KeyHash)
+}
+
+// RevokeUserSessions ends every cookie session of the user
+func (s *SessionService) RevokeUserSessions(ctx context.Context, userID string) error {
+	user_id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+	return s.Store.DeleteUser(ctx, user_id)
+}
+
+// ##########################################################
+// ##########  Session Stores
+// ##########################################################
+
+type mongoSessionStore struct {
+	collection *mongo.Collection
+}
+
+func (m *mongoSessionStore) Get(ctx context.Context, keyHash string) (*models.Session, error) {
+	var session models.Session
+	if err := m.collection.FindOne(ctx, bson.M{"key_hash": keyHash}).Decode(&session); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrSessionInvalid
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (m *mongoSessionStore) Save(ctx context.Context, session *models.Session) error {
+	if _, err := m.collection.InsertOne(ctx, session); err != nil {
+		return fmt.Errorf("insert failed: %w", err)
+	}
+	return nil
+}
+
+func (m *mongoSessionStore) Touch(ctx context.Context, session *models.Session, seenAt time.Time) error {
+	_, err := m.collection.UpdateOne(ctx,
+		bson.M{"_id": session.ID},
+		bson.M{"$set": bson.M{"last_seen_at": seenAt}},
+	)
+	return err
+}
+
+func (m *mongoSessionStore) Delete(ctx context.Context, keyHash string) error {
+	_, err := m.collection.DeleteOne(ctx, bson.M{"key_hash": keyHash})
+	return err
+}
+
+func (m *mongoSessionStore) DeleteUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := m.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
+
+// the cache can not list the sessions of a user, revoking them stores a cut off time instead.
+// The cut off is kept in mongo, the cache may evict it and a revoked session must not come back
+type cacheSessionStore struct {
+	revocations *mongo.Collection
+}
+
+// sessions of the user created up to the returned time are revoked, zero when never revoked
+func (c *cacheSessionStore) revokedAt(ctx context.Context, userID primitive.ObjectID) (time.Time, error) {
+	cacheKey := "sessions_revoked:" + userID.Hex()
+	if value, found := AppCacheService.Get(cacheKey); found {
+		return value.(time.Time), nil
+	}
+
+	var revocation models.SessionRevocation
+	err := c.revocations.FindOne(ctx, bson.M{"_id": userID}).Decode(&revocation)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, err
+	}
+
+	// users without a revocation are looked up again after a minute
+	ttl := time.Minute
+	if !revocation.RevokedAt.IsZero() {
+		ttl = time.Until(revocation.ExpiresAt)
+	}
+	AppCacheService.SetWithTTL(cacheKey, revocation.RevokedAt, ttl)
+	return revocation.RevokedAt, nil
+}
+
+func (c *cacheSessionStore) Get(ctx context.Context, keyHash string) (*models.Session, error) {
+	value, found := AppCacheService.Get("session:" + keyHash)
+	if !found {
+		return nil, ErrSessionInvalid
+	}
+	session := *value.(*models.Session)
+
+	revokedAt, err := c.revokedAt(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !revokedAt.IsZero() && !session.CreatedAt.After(revokedAt) {
+		return nil, ErrSessionInvalid
+	}
+	return &session, nil
+}
+
+func (c *cacheSessionStore) Save(ctx context.Context, session *models.Session) error {
+	stored := *session
+	if !AppCacheService.SetWithTTL("session:"+session.KeyHash, &stored, time.Until(session.ExpiresAt)) {
+		return errors.New("save session failed: rejected by the cache")
+	}
+	// the session has to be readable by the very next request
+	AppCacheService.Wait()
+	return nil
+}
+
+func (c *cacheSessionStore) Touch(ctx context.Context, session *models.Session, seenAt time.Time) error {
+	touched := *session
+	touched.LastSeenAt = seenAt
+	return c.Save(ctx, &touched)
+}
+
+func (c *cacheSessionStore) Delete(ctx context.Context, keyHash string) error {
+	AppCacheService.Delete("session:" + keyHash)
+	return nil
+}
+
+func (c *cacheSessionStore) DeleteUser(ctx context.Context, userID primitive.ObjectID) error {
+	maxLifetime, _ := sessionLifetimes()
+	now := time.Now()
+	revocation := models.SessionRevocation{
+		UserID:    userID,
+		RevokedAt: now,
+		ExpiresAt: now.Add(maxLifetime),
+	}
+	_, err := c.revocations.ReplaceOne(ctx, bson.M{"_id": userID}, revocation, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("revoke sessions failed: %w", err)
+	}
+
+	AppCacheService.SetWithTTL("sessions_revoked:"+userID.Hex(), now, maxLifetime)
+	AppCacheService.Wait()
+	return nil
+}
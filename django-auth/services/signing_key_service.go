@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
+	"github.com/jinzhu/copier"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var HandlerSigningKeyService SigningKeyService
+
+// SigningKeyStore persists the key ring, picked with SIGNING_KEY_STORE (mongo or file)
+type SigningKeyStore interface {
+	List(ctx context.Context) ([]models.SigningKey, error)
+	Save(ctx context.Context, key *models.SigningKey) error
+	Delete(ctx context.Context, kid string) error
+}
+
+// SigningKeyService manages the token signing key ring
+type SigningKeyService struct {
+	Collection *mongo.Collection
+	Client     *mongo.Client
+	Database   *mongo.Database
+	Store      SigningKeyStore
+}
+
+// Constructor For Client
+func NewSigningKeyService(client *mongo.Client) (*SigningKeyService, error) {
+	database := client.Database("django_auth")
+	collection := database.Collection("SigningKeys")
+
+	var store SigningKeyStore
+	switch kind := configs.AppConfig.GetOrDefault("SIGNING_KEY_STORE", "mongo"); kind {
+	case "mongo":
+		store = &mongoSigningKeyStore{collection: collection}
+	case "file":
+		dir := configs.AppConfig.GetOrDefault("SIGNING_KEY_DIR", "keys")
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("create signing key directory failed: %w", err)
+		}
+		store = &fileSigningKeyStore{dir: dir}
+	default:
+		return nil, fmt.Errorf("unknown signing key store: %s", kind)
+	}
+
+	HandlerSigningKeyService = SigningKeyService{
+		Collection: collection,
+		Client:     client,
+		Database:   database,
+		Store:      store,
+	}
+	return &HandlerSigningKeyService, nil
+}
+
+// EnsureIndexes creates the kid index when keys are kept in mongo
+func (s *SigningKeyService) EnsureIndexes(ctx context.Context) error {
+	if _, ok := s.Store.(*mongoSigningKeyStore); !ok {
+		return nil
+	}
+	_, err := s.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "kid", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// how often running instances reload the ring, see bluetasks
+func SigningKeyReloadInterval() time.Duration {
+	minutes, err := strconv.Atoi(configs.AppConfig.GetOrDefault("SIGNING_KEY_RELOAD_MINUTES", "5"))
+	if err != nil || minutes <= 0 {
+		minutes = 5
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// Load reads the ring from the store into memory, an empty ring is bootstrapped with a first rotation
+func (s *SigningKeyService) Load(ctx context.Context) error {
+	keys, err := s.Store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(keys, func(key models.SigningKey) bool { return key.State == utils.KeyStateActive }) {
+		_, err := s.Rotate(ctx)
+		return err
+	}
+
+	// the newest active key signs if instances ever activated two at once
+	slices.SortFunc(keys, func(a, b models.SigningKey) int { return b.ActivatedAt.Compare(a.ActivatedAt) })
+
+	entries := make([]utils.KeyRingEntry, 0, len(keys))
+	for _, key := range keys {
+		signer, err := utils.DecodePrivateKey([]byte(key.PrivateKey))
+		if err != nil {
+			return fmt.Errorf("signing key %s: %w", key.Kid, err)
+		}
+		entries = append(entries, utils.KeyRingEntry{
+			Kid:         key.Kid,
+			Algorithm:   key.Algorithm,
+			State:       key.State,
+			RetireAfter: key.RetireAfter,
+			Key:         signer,
+		})
+	}
+
+	utils.SetKeyRing(entries)
+	return nil
+}
+
+// generates a key with SIGNING_KEY_ALGORITHM
+func newSigningKey(state string) (*models.SigningKey, error) {
+	algorithm := configs.AppConfig.GetOrDefault("SIGNING_KEY_ALGORITHM", "RS256")
+	signer, err := utils.GenerateSigningKey(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	kid, err := utils.KeyID(signer)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := utils.EncodePrivateKey(signer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SigningKey{
+		ID:         primitive.NewObjectID(),
+		Kid:        kid,
+		Algorithm:  algorithm,
+		State:      state,
+		PrivateKey: encoded,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// Rotate promotes the next key to active, retires the active key and creates a new next key.
+// Retired keys are kept until every token they signed has expired and then pruned.
+func (s *SigningKeyService) Rotate(ctx context.Context) ([]models.SigningKeyGet, error) {
+	keys, err := s.Store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	// instances keep signing with the old key until their next reload, that window is added on top
+	retireAfter := now.Add(utils.MaxTokenLifetime() + SigningKeyReloadInterval())
+
+	// publishing the next key first so it is known before anything is signed with it
+	next, err := newSigningKey(utils.KeyStateNext)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Store.Save(ctx, next); err != nil {
+		return nil, err
+	}
+
+	var promoted *models.SigningKey
+	for index := range keys {
+		if keys[index].State == utils.KeyStateNext {
+			promoted = &keys[index]
+			break
+		}
+	}
+
+	// without a next key (first start) the new active key is used right away
+	if promoted == nil {
+		if promoted, err = newSigningKey(utils.KeyStateNext); err != nil {
+			return nil, err
+		}
+	}
+	promoted.State = utils.KeyStateActive
+	promoted.ActivatedAt = now
+	if err := s.Store.Save(ctx, promoted); err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		switch {
+		case key.Kid == promoted.Kid:
+		case key.State == utils.KeyStateActive:
+			key.State = utils.KeyStateRetired
+			key.RetiredAt = now
+			key.RetireAfter = retireAfter
+			if err := s.Store.Save(ctx, &key); err != nil {
+				return nil, err
+			}
+		case key.State == utils.KeyStateRetired && now.After(key.RetireAfter):
+			if err := s.Store.Delete(ctx, key.Kid); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.Load(ctx); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx)
+}
+
+// Get lists the keys of the ring without their private part
+func (s *SigningKeyService) Get(ctx context.Context) ([]models.SigningKeyGet, error) {
+	keys, err := s.Store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(keys, func(a, b models.SigningKey) int { return b.CreatedAt.Compare(a.CreatedAt) })
+
+	signingKeys := []models.SigningKeyGet{}
+	if err := copier.Copy(&signingKeys, keys); err != nil {
+		return nil, err
+	}
+	return signingKeys, nil
+}
+
+// ##########################################################
+// ##########  Signing Key Stores
+// ##########################################################
+
+type mongoSigningKeyStore struct {
+	collection *mongo.Collection
+}
+
+func (m *mongoSigningKeyStore) List(ctx context.Context) ([]models.SigningKey, error) {
+	cursor, err := m.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	keys := []models.SigningKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode signing keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (m *mongoSigningKeyStore) Save(ctx context.Context, key *models.SigningKey) error {
+	_, err := m.collection.ReplaceOne(ctx, bson.M{"kid": key.Kid}, key, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("save signing key failed: %w", err)
+	}
+	return nil
+}
+
+func (m *mongoSigningKeyStore) Delete(ctx context.Context, kid string) error {
+	_, err := m.collection.DeleteOne(ctx, bson.M{"kid": kid})
+	return err
+}
+
+// keys are stored one json file per kid, readable by the owner only
+type fileSigningKeyStore struct {
+	dir string
+}
+
+func (f *fileSigningKeyStore) List(ctx context.Context) ([]models.SigningKey, error) {
+	names, err := filepath.Glob(filepath.Join(f.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []models.SigningKey{}
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("read signing key failed: %w", err)
+		}
+		var key models.SigningKey
+		if err := json.Unmarshal(data, &key); err != nil {
+			return nil, fmt.Errorf("decode signing key %s failed: %w", filepath.Base(name), err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (f *fileSigningKeyStore) path(kid string) (string, error) {
+	if kid == "" || strings.ContainsAny(kid, `/\.`) {
+		return "", errors.New("invalid kid")
+	}
+	return filepath.Join(f.dir, kid+".json"), nil
+}
+
+func (f *fileSigningKeyStore) Save(ctx context.Context, key *models.SigningKey) error {
+	name, err := f.path(key.Kid)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(key, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// writing to a temporary file first so readers never see half a key
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("save signing key failed: %w", err)
+	}
+	return os.Rename(tmp, name)
+}
+
+func (f *fileSigningKeyStore) Delete(ctx context.Context, kid string) error {
+	name, err := f.path(kid)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
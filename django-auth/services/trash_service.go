@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ##########################################################
+// ##########  Trash Services
+// ##########################################################
+
+// SOFT_DELETE_ENABLED, true by default. Deleted users, groups and permissions go to the trash
+// and are only removed for good once TRASH_RETENTION_DAYS passed.
+func SoftDeleteEnabled() bool {
+	return configs.AppConfig.GetOrDefault("SOFT_DELETE_ENABLED", "true") != "false"
+}
+
+// TRASH_RETENTION_DAYS, 30 by default
+func trashRetention() time.Duration {
+	days, err := strconv.Atoi(configs.AppConfig.GetOrDefault("TRASH_RETENTION_DAYS", "30"))
+	if err != nil || days <= 0 {
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// notDeleted adds the condition hiding documents in the trash to the filter
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = nil
+	return filter
+}
+
+// filter matching documents in the trash only
+func inTrash() bson.M {
+	return bson.M{"deleted_at": bson.M{"$ne": nil}}
+}
+
+// moves the document to the trash, missing is returned when it does not exist or is in the trash already
+func softDelete(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID, deletedBy string, expectedVersion *int64, missing error) error {
+	now := time.Now()
+	result, err := collection.UpdateOne(ctx, matchVersion(notDeleted(bson.M{"_id": id}), expectedVersion), bson.M{
+		"$set": bson.M{"deleted_at": now, "deleted_by": deletedBy, "updated_at": now},
+		"$inc": incrementVersion,
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return missingOrStale(ctx, collection, id, expectedVersion, missing)
+	}
+	return nil
+}
+
+// takes the document out of the trash, missing is returned when it is not in the trash
+func restore(ctx context.Context, collection *mongo.Collection, objID primitive.ObjectID, missing error) error {
+	filter := inTrash()
+	filter["_id"] = objID
+	result, err := collection.UpdateOne(ctx, filter, bson.M{
+		"$unset": bson.M{"deleted_at": "", "deleted_by": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+		"$inc":   incrementVersion,
+	})
+	if err != nil {
+		// a live document took the name while this one was in the trash
+		if conflict := uniqueConflict(err); conflict != nil {
+			return conflict
+		}
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return missing
+	}
+	return nil
+}
+
+// PurgeTrash removes users, groups and permissions that spent longer than TRASH_RETENTION_DAYS in the trash,
+// with the same cleanup of references as a delete without the trash. Returns the number of documents removed.
+func PurgeTrash(ctx context.Context) (int64, error) {
+	cutoff := time.Now().Add(-trashRetention())
+
+	var purged int64
+	for _, trash := range []struct {
+		collection *mongo.Collection
+		hardDelete func(context.Context, primitive.ObjectID, time.Time, *int64) error
+	}{
+		{HandlerUserService.Collection, HandlerUserService.hardDelete},
+		{HandlerGroupService.Collection, HandlerGroupService.hardDelete},
+		{HandlerPermissionService.Collection, HandlerPermissionService.hardDelete},
+	} {
+		ids, err := existingIDs(ctx, trash.collection, bson.M{"deleted_at": bson.M{"$lt": cutoff}})
+		if err != nil {
+			return purged, err
+		}
+		for id := range ids {
+			// restored in the meantime
+			err := trash.hardDelete(ctx, id, cutoff, nil)
+			if errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrGroupNotFound) || errors.Is(err, ErrPermissionNotFound) {
+				continue
+			}
+			if err != nil {
+				return purged, err
+			}
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ConflictError is returned when a write would repeat a value kept unique by an index
+type ConflictError struct {
+	Resource string
+	Field    string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("a %s with that %s already exists", e.Resource, e.Field)
+}
+
+// Code names the conflict in field errors, username_taken for a username
+func (e *ConflictError) Code() string {
+	return e.Field + "_taken"
+}
+
+var (
+	ErrUsernameTaken           = &ConflictError{Resource: "user", Field: "username"}
+	ErrEmailTaken              = &ConflictError{Resource: "user", Field: "email"}
+	ErrGroupNameTaken          = &ConflictError{Resource: "group", Field: "name"}
+	ErrPermissionCodenameTaken = &ConflictError{Resource: "permission", Field: "codename"}
+)
+
+// usernames and emails compare ignoring case, Alice and alice are the same user
+var caseInsensitiveCollation = &options.Collation{Locale: "en", Strength: 2}
+
+// unique indexes by their default name, the names differ across the collections
+var uniqueIndexConflicts = map[string]*ConflictError{
+	"username_1_deleted_at_1": ErrUsernameTaken,
+	"email_1_deleted_at_1":    ErrEmailTaken,
+	"name_1_deleted_at_1":     ErrGroupNameTaken,
+	"codename_1_deleted_at_1": ErrPermissionCodenameTaken,
+}
+
+// uniqueConflict returns the conflict of a duplicate key error on one of the unique indexes, nil for any other error
+func uniqueConflict(err error) *ConflictError {
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	for index, conflict := range uniqueIndexConflicts {
+		if strings.Contains(err.Error(), "index: "+index+" ") {
+			return conflict
+		}
+	}
+	return nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/bushubdegefu/m-playground/django-auth/models"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
 	"github.com/jinzhu/copier"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -35,35 +37,53 @@ func NewUserService(client *mongo.Client) (*UserService, error) {
 	return &HandlerUserService, nil
 }
 
-// Utility function for transactions
-func (s *UserService) withTransaction(ctx context.Context, fn func(sc mongo.SessionContext) error) error {
-	session, err := s.Client.StartSession()
-	if err != nil {
-		return fmt.Errorf("start session failed: %w", err)
-	}
-	defer session.EndSession(ctx)
-
-	return mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
-		if err := session.StartTransaction(); err != nil {
-			return err
-		}
-		if err := fn(sc); err != nil {
-			session.AbortTransaction(sc)
-			return err
-		}
-		return session.CommitTransaction(sc)
+// EnsureIndexes makes usernames and emails unique ignoring case, users without an email are left out.
+// deleted_at is part of the keys so users in the trash do not hold on to their username and email
+func (s *UserService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.Collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "username", Value: 1}, {Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetUnique(true).SetCollation(caseInsensitiveCollation),
+		},
+		{
+			Keys: bson.D{{Key: "email", Value: 1}, {Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetUnique(true).SetCollation(caseInsensitiveCollation).
+				SetPartialFilterExpression(bson.M{"email": bson.M{"$gt": ""}}),
+		},
 	})
+	return err
+}
+
+// user fields a password is compared against by the similarity validator
+func passwordUserAttributes(username, firstName, lastName, email string) map[string]string {
+	return map[string]string{
+		"username":   username,
+		"first_name": firstName,
+		"last_name":  lastName,
+		"email":      email,
+	}
 }
 
 // Create inserts a new user
 func (s *UserService) Create(ctx context.Context, posted_user *models.UserPost) (*models.UserGet, error) {
 	var createdUser = new(models.UserGet)
 
-	err := s.withTransaction(ctx, func(sc mongo.SessionContext) error {
-		hashedPassword := models.HashFunc(posted_user.Password)
+	// the password policy is checked before anything is written
+	err := utils.ValidatePassword(posted_user.Password, passwordUserAttributes(
+		posted_user.Username, posted_user.FirstName, posted_user.LastName, posted_user.Email))
+	if err != nil {
+		return nil, err
+	}
+
+	userID := primitive.NewObjectID()
+	err = audited(ctx, s.Collection, models.ObjectTypeUser, models.AuditActionCreate, userID, func(sc mongo.SessionContext) error {
+		hashedPassword, err := models.HashFunc(posted_user.Password)
+		if err != nil {
+			return fmt.Errorf("hashing password failed: %w", err)
+		}
 
 		user := models.User{
-			ID:          primitive.NewObjectID(),
+			ID:          userID,
 			Password:    hashedPassword,
 			IsSuperuser: posted_user.IsSuperuser,
 			Username:    posted_user.Username,
@@ -72,11 +92,15 @@ func (s *UserService) Create(ctx context.Context, posted_user *models.UserPost)
 			Email:       posted_user.Email,
 			IsStaff:     posted_user.IsStaff,
 			IsActive:    posted_user.IsActive,
+			Version:     1,
 			CreatedAt:   time.Now(),
 		}
 
-		_, err := s.Collection.InsertOne(ctx, user)
+		_, err = s.Collection.InsertOne(sc, user)
 		if err != nil {
+			if conflict := uniqueConflict(err); conflict != nil {
+				return conflict
+			}
 			return fmt.Errorf("insert failed: %w", err)
 		}
 
@@ -106,7 +130,7 @@ func (s *UserService) GetOne(ctx context.Context, id string) (*models.UserGet, e
 	}
 
 	var user models.UserGet
-	err = s.Collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&user)
+	err = s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&user)
 	if err != nil {
 		return nil, err
 	}
@@ -121,7 +145,7 @@ func (s *UserService) GetOne(ctx context.Context, id string) (*models.UserGet, e
 func (s *UserService) Get(ctx context.Context, pagination models.Pagination, searchFields []string, searchTerm []string) ([]models.UserGet, uint, error) {
 
 	// Build search query if any
-	filter := bson.M{}
+	filter := notDeleted(bson.M{})
 	if len(searchTerm) > 0 && len(searchFields) > 0 && len(searchFields) >= len(searchTerm) {
 		var orConditions []bson.M
 		for index, term := range searchTerm {
@@ -160,20 +184,45 @@ func (s *UserService) Get(ctx context.Context, pagination models.Pagination, sea
 }
 
 // Update modifies a Users by ID
-func (s *UserService) Update(ctx context.Context, patch_user *models.UserPatch, id string) (*models.UserGet, error) {
+func (s *UserService) Update(ctx context.Context, patch_user *models.UserPatch, id string, expectedVersion *int64) (*models.UserGet, error) {
 	// update User
-	var updatedUser *models.UserGet
+	var updatedUser = new(models.UserGet)
 
 	user_id, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return &models.UserGet{}, fmt.Errorf("invalid ID: %w", err)
 	}
 
-	err = s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+	// a new password is compared against the user fields as they will be after the update
+	if patch_user.Password != nil {
+		user, err := s.findUser(ctx, id)
+		if err != nil {
+			return &models.UserGet{}, err
+		}
+		attributes := passwordUserAttributes(user.Username, user.FirstName, user.LastName, user.Email)
+		for name, value := range map[string]*string{
+			"username":   patch_user.Username,
+			"first_name": patch_user.FirstName,
+			"last_name":  patch_user.LastName,
+			"email":      patch_user.Email,
+		} {
+			if value != nil {
+				attributes[name] = *value
+			}
+		}
+		if err := utils.ValidatePassword(*patch_user.Password, attributes); err != nil {
+			return &models.UserGet{}, err
+		}
+	}
+
+	err = audited(ctx, s.Collection, models.ObjectTypeUser, models.AuditActionUpdate, user_id, func(sc mongo.SessionContext) error {
 		updateFields := bson.M{}
 		if patch_user.Password != nil {
 			// setting password string to hash
-			hashedPassword := models.HashFunc(*patch_user.Password)
+			hashedPassword, err := models.HashFunc(*patch_user.Password)
+			if err != nil {
+				return fmt.Errorf("hashing password failed: %w", err)
+			}
 			updateFields["password"] = hashedPassword
 		}
 		if patch_user.IsSuperuser != nil {
@@ -200,92 +249,178 @@ func (s *UserService) Update(ctx context.Context, patch_user *models.UserPatch,
 		updateFields["updated_at"] = time.Now()
 
 		// filter to use to update value by
-		filterUser := bson.M{"_id": user_id}
-		updateUser := bson.M{"$set": updateFields}
-		// Update the document by ID
-		_, err := s.Collection.UpdateOne(ctx, filterUser, updateUser)
+		filterUser := matchVersion(notDeleted(bson.M{"_id": user_id}), expectedVersion)
+		updateUser := bson.M{"$set": updateFields, "$inc": incrementVersion}
+		// Update the document by ID, returning it as stored after the update
+		err := s.Collection.FindOneAndUpdate(sc, filterUser, updateUser,
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(updatedUser)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return missingOrStale(sc, s.Collection, user_id, expectedVersion, ErrUserNotFound)
+		}
 		if err != nil {
-			return fmt.Errorf("insert failed: %w", err)
+			if conflict := uniqueConflict(err); conflict != nil {
+				return conflict
+			}
+			return fmt.Errorf("update failed: %w", err)
 		}
 
-		// Removing Cache if update sucess
-		cacheKey := "user:" + id
-		AppCacheService.Delete(cacheKey)
-
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	copier.Copy(&updatedUser, patch_user)
-	return updatedUser, err
+	// Removing Cache once the update is committed, a read before the commit would cache the old version again
+	AppCacheService.Delete("user:" + id)
+	invalidateEffectivePermissions()
+
+	return updatedUser, nil
 }
 
-// Delete removes a user by ID
-func (s *UserService) Delete(ctx context.Context, id string) error {
+// Delete moves a user to the trash, or removes it for good when SOFT_DELETE_ENABLED is false
+func (s *UserService) Delete(ctx context.Context, id string, deletedBy string, expectedVersion *int64) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid ID: %w", err)
+	}
 
-	err := s.withTransaction(ctx, func(sc mongo.SessionContext) error {
-		objID, err := primitive.ObjectIDFromHex(id)
-		if err != nil {
-			return fmt.Errorf("invalid ID: %w", err)
-		}
+	if SoftDeleteEnabled() {
+		err = audited(ctx, s.Collection, models.ObjectTypeUser, models.AuditActionDelete, objID, func(sc mongo.SessionContext) error {
+			return softDelete(sc, s.Collection, objID, deletedBy, expectedVersion, ErrUserNotFound)
+		})
+	} else {
+		err = s.hardDelete(ctx, objID, time.Time{}, expectedVersion)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Removing Cache if delete sucess
+	cacheKey := "user:" + id
+	AppCacheService.Delete(cacheKey)
+	invalidateEffectivePermissions()
+
+	// a deleted user keeps no session
+	if err := HandlerRefreshTokenService.RevokeUserSessions(ctx, id); err != nil {
+		return err
+	}
+	if err := HandlerSessionService.RevokeUserSessions(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// hardDelete removes a user for good, with trashedBefore set only when it went to the trash before then
+// and expectedVersion set only when it must still be that version
+func (s *UserService) hardDelete(ctx context.Context, objID primitive.ObjectID, trashedBefore time.Time, expectedVersion *int64) error {
+	filter := matchVersion(bson.M{"_id": objID}, expectedVersion)
+	if !trashedBefore.IsZero() {
+		filter["deleted_at"] = bson.M{"$lt": trashedBefore}
+	}
 
-		result, err := s.Collection.DeleteOne(ctx, bson.M{"_id": objID})
+	// a delete without the trash or the scheduled purge of the trash
+	action := models.AuditActionDelete
+	if !trashedBefore.IsZero() {
+		action = models.AuditActionPurge
+	}
+
+	err := audited(ctx, s.Collection, models.ObjectTypeUser, action, objID, func(sc mongo.SessionContext) error {
+		result, err := s.Collection.DeleteOne(sc, filter)
 		if err != nil {
 			return err
 		}
 
 		if result.DeletedCount == 0 {
-			return errors.New("no document deleted")
+			return missingOrStale(sc, s.Collection, objID, expectedVersion, ErrUserNotFound)
 		}
 
-		// Removing Cache if delete sucess
-		cacheKey := "user:" + id
-		AppCacheService.Delete(cacheKey)
+		// grants to the user and on the user go with it
+		if err := HandlerObjectPermissionService.deleteReferencing(sc, "user_id", models.ObjectTypeUser, objID); err != nil {
+			return err
+		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	AppCacheService.Delete("user:" + objID.Hex())
+	invalidateEffectivePermissions()
+	return nil
 }
 
-// ##########################################################
-// ##########  Relationship  Services to Permission
-// ##########################################################
+// Restore takes a user out of the trash
+func (s *UserService) Restore(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
 
-func (s *UserService) AddUserToPermission(ctx context.Context, userID, permissionID string) error {
-	user_id, err := primitive.ObjectIDFromHex(userID)
-	permission_id, err := primitive.ObjectIDFromHex(permissionID)
+	err = audited(ctx, s.Collection, models.ObjectTypeUser, models.AuditActionRestore, objID, func(sc mongo.SessionContext) error {
+		return restore(sc, s.Collection, objID, ErrUserNotFound)
+	})
 	if err != nil {
 		return err
 	}
 
-	_, err = s.Collection.UpdateOne(ctx, bson.M{"_id": user_id}, bson.M{
-		"$addToSet": bson.M{"permission_ids": permission_id}, // Prevents duplicates
-	})
-	return err
+	AppCacheService.Delete("user:" + id)
+	invalidateEffectivePermissions()
+	return nil
 }
 
-func (s *UserService) RemoveUserFromPermission(ctx context.Context, userID, permissionID string) error {
-	user_id, err := primitive.ObjectIDFromHex(userID)
-	permission_id, err := primitive.ObjectIDFromHex(permissionID)
+// GetTrash returns the users in the trash with pagination, most recently deleted first
+func (s *UserService) GetTrash(ctx context.Context, pagination models.Pagination) ([]models.UserGet, uint, error) {
+	filter := inTrash()
+
+	//pagination logic
+	opts := options.Find().
+		SetSort(bson.D{{Key: "deleted_at", Value: -1}}).
+		SetSkip(int64(pagination.Page * pagination.Size)).
+		SetLimit(int64(pagination.Size))
+
+	totalCount, _ := s.Collection.CountDocuments(ctx, filter)
+
+	cursor, err := s.Collection.Find(ctx, filter, opts)
 	if err != nil {
-		return err
+		return nil, uint(totalCount), err
 	}
+	defer cursor.Close(ctx)
 
-	_, err = s.Collection.UpdateOne(ctx, bson.M{"_id": user_id}, bson.M{
-		"$pull": bson.M{"permission_ids": permission_id},
-	})
-	return err
+	var users []models.UserGet
+	for cursor.Next(ctx) {
+		var item models.UserGet
+		if err := cursor.Decode(&item); err != nil {
+			return nil, uint(totalCount), err
+		}
+		users = append(users, item)
+	}
+
+	return users, uint(totalCount), nil
+}
+
+// ##########################################################
+// ##########  Relationship  Services to Permission
+// ##########################################################
+
+func (s *UserService) AddUserToPermission(ctx context.Context, userID, permissionID string) error {
+	return relation{s.Collection, models.ObjectTypeUser, ErrUserNotFound, "permission_ids", HandlerPermissionService.Collection, ErrPermissionNotFound}.add(ctx, userID, permissionID)
+}
+
+func (s *UserService) RemoveUserFromPermission(ctx context.Context, userID, permissionID string) error {
+	return relation{s.Collection, models.ObjectTypeUser, ErrUserNotFound, "permission_ids", HandlerPermissionService.Collection, ErrPermissionNotFound}.remove(ctx, userID, permissionID)
 }
 
 func (s *UserService) GetUserPermissions(ctx context.Context, userID string, pagination models.Pagination) ([]models.Permission, uint, error) {
 	user_id, _ := primitive.ObjectIDFromHex(userID)
 	var user models.User
-	if err := s.Collection.FindOne(ctx, bson.M{"_id": user_id}).Decode(&user); err != nil {
+	if err := s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": user_id})).Decode(&user); err != nil {
 		return nil, 0, err
 	}
 
 	permissionCollection := s.Database.Collection("Permissions")
-	filter := bson.M{"_id": bson.M{"$in": user.PermissionIDs}}
+	filter := notDeleted(bson.M{"_id": bson.M{"$in": user.PermissionIDs}})
 	opts := options.Find().
 		SetSkip(int64(pagination.Page * pagination.Size)).
 		SetLimit(int64(pagination.Size))
@@ -319,12 +454,12 @@ func (s *UserService) GetAllPermissionsForUser(ctx context.Context, userID strin
 	}
 
 	var user models.User
-	if err := s.Collection.FindOne(ctx, bson.M{"_id": user_id}).Decode(&user); err != nil {
+	if err := s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": user_id})).Decode(&user); err != nil {
 		return nil, fmt.Errorf("failed to fetch user: %w", err)
 	}
 
 	permissionCollection := s.Database.Collection("Permissions")
-	filter := bson.M{"_id": bson.M{"$in": user.PermissionIDs}}
+	filter := notDeleted(bson.M{"_id": bson.M{"$in": user.PermissionIDs}})
 
 	cursor, err := permissionCollection.Find(ctx, filter)
 	if err != nil {
@@ -351,13 +486,13 @@ func (s *UserService) GetAllPermissionsuserDoesNotHave(ctx context.Context, user
 	}
 
 	var user models.User
-	if err := s.Collection.FindOne(ctx, bson.M{"_id": user_id}).Decode(&user); err != nil {
+	if err := s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": user_id})).Decode(&user); err != nil {
 		return nil, fmt.Errorf("failed to fetch user: %w", err)
 	}
 
 	permissionCollection := s.Database.Collection("Permissions")
 
-	filter := bson.M{}
+	filter := notDeleted(bson.M{})
 	if len(user.PermissionIDs) > 0 {
 		filter["_id"] = bson.M{"$nin": user.PermissionIDs}
 	}
@@ -385,40 +520,22 @@ func (s *UserService) GetAllPermissionsuserDoesNotHave(ctx context.Context, user
 // ##########################################################
 
 func (s *UserService) AddUserToGroup(ctx context.Context, userID, groupID string) error {
-	user_id, err := primitive.ObjectIDFromHex(userID)
-	group_id, err := primitive.ObjectIDFromHex(groupID)
-	if err != nil {
-		return err
-	}
-
-	_, err = s.Collection.UpdateOne(ctx, bson.M{"_id": user_id}, bson.M{
-		"$addToSet": bson.M{"group_ids": group_id}, // Prevents duplicates
-	})
-	return err
+	return relation{s.Collection, models.ObjectTypeUser, ErrUserNotFound, "group_ids", HandlerGroupService.Collection, ErrGroupNotFound}.add(ctx, userID, groupID)
 }
 
 func (s *UserService) RemoveUserFromGroup(ctx context.Context, userID, groupID string) error {
-	user_id, err := primitive.ObjectIDFromHex(userID)
-	group_id, err := primitive.ObjectIDFromHex(groupID)
-	if err != nil {
-		return err
-	}
-
-	_, err = s.Collection.UpdateOne(ctx, bson.M{"_id": user_id}, bson.M{
-		"$pull": bson.M{"group_ids": group_id},
-	})
-	return err
+	return relation{s.Collection, models.ObjectTypeUser, ErrUserNotFound, "group_ids", HandlerGroupService.Collection, ErrGroupNotFound}.remove(ctx, userID, groupID)
 }
 
 func (s *UserService) GetUserGroups(ctx context.Context, userID string, pagination models.Pagination) ([]models.Group, uint, error) {
 	user_id, _ := primitive.ObjectIDFromHex(userID)
 	var user models.User
-	if err := s.Collection.FindOne(ctx, bson.M{"_id": user_id}).Decode(&user); err != nil {
+	if err := s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": user_id})).Decode(&user); err != nil {
 		return nil, 0, err
 	}
 
 	groupCollection := s.Database.Collection("Groups")
-	filter := bson.M{"_id": bson.M{"$in": user.GroupIDs}}
+	filter := notDeleted(bson.M{"_id": bson.M{"$in": user.GroupIDs}})
 	opts := options.Find().
 		SetSkip(int64(pagination.Page * pagination.Size)).
 		SetLimit(int64(pagination.Size))
@@ -452,12 +569,12 @@ func (s *UserService) GetAllGroupsForUser(ctx context.Context, userID string) ([
 	}
 
 	var user models.User
-	if err := s.Collection.FindOne(ctx, bson.M{"_id": user_id}).Decode(&user); err != nil {
+	if err := s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": user_id})).Decode(&user); err != nil {
 		return nil, fmt.Errorf("failed to fetch user: %w", err)
 	}
 
 	groupCollection := s.Database.Collection("Groups")
-	filter := bson.M{"_id": bson.M{"$in": user.GroupIDs}}
+	filter := notDeleted(bson.M{"_id": bson.M{"$in": user.GroupIDs}})
 
 	cursor, err := groupCollection.Find(ctx, filter)
 	if err != nil {
@@ -484,13 +601,13 @@ func (s *UserService) GetAllGroupsuserDoesNotHave(ctx context.Context, userID st
 	}
 
 	var user models.User
-	if err := s.Collection.FindOne(ctx, bson.M{"_id": user_id}).Decode(&user); err != nil {
+	if err := s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": user_id})).Decode(&user); err != nil {
 		return nil, fmt.Errorf("failed to fetch user: %w", err)
 	}
 
 	groupCollection := s.Database.Collection("Groups")
 
-	filter := bson.M{}
+	filter := notDeleted(bson.M{})
 	if len(user.GroupIDs) > 0 {
 		filter["_id"] = bson.M{"$nin": user.GroupIDs}
 	}
@@ -516,3 +633,192 @@ func (s *UserService) GetAllGroupsuserDoesNotHave(ctx context.Context, userID st
 // ##########################################################
 // ##########  Custom Services Add Here   ###################
 // ##########################################################
+
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInactiveUser       = errors.New("user account is disabled")
+)
+
+// Authenticate tries the AUTH_BACKENDS in order and stamps the last login time of the user that matched
+func (s *UserService) Authenticate(ctx context.Context, username, password string) (*models.User, error) {
+	var user *models.User
+	var backendErr error
+	for _, backend := range AuthBackends {
+		found, err := backend.Authenticate(ctx, username, password)
+		if err == nil {
+			user = found
+			break
+		}
+		if errors.Is(err, ErrInactiveUser) {
+			return nil, err
+		}
+		if !errors.Is(err, ErrInvalidCredentials) {
+			// an unreachable backend should not lock out the users of the others
+			log.Printf("auth backend %s failed: %v", backend.Name(), err)
+			backendErr = err
+		}
+	}
+
+	if user == nil {
+		if backendErr != nil {
+			return nil, backendErr
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	if !user.IsActive {
+		return nil, ErrInactiveUser
+	}
+
+	user.LastLogin = time.Now()
+	err := audited(withUserAsActor(ctx, user.ID.Hex(), user.Username), s.Collection, models.ObjectTypeUser, models.AuditActionUpdate, user.ID, func(sc mongo.SessionContext) error {
+		_, err := s.Collection.UpdateOne(sc, bson.M{"_id": user.ID}, bson.M{
+			"$set": bson.M{"last_login": user.LastLogin},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update last login failed: %w", err)
+	}
+
+	// Removing Cache since last login changed
+	cacheKey := "user:" + user.ID.Hex()
+	AppCacheService.Delete(cacheKey)
+
+	return user, nil
+}
+
+// GetEffectivePermissions returns the codenames of permissions the user holds directly or through any of their groups
+// superusers get the "superuser" entry so utils.CheckValueExistsInSlice lets them through every check
+func (s *UserService) GetEffectivePermissions(ctx context.Context, userID string) ([]string, error) {
+	user_id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var user models.User
+	if err := s.Collection.FindOne(ctx, notDeleted(bson.M{"_id": user_id})).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+
+	if !user.IsActive {
+		return nil, ErrInactiveUser
+	}
+
+	if user.IsSuperuser {
+		return []string{"superuser"}, nil
+	}
+
+	permissionIDs := append([]primitive.ObjectID{}, user.PermissionIDs...)
+	if len(user.GroupIDs) > 0 {
+		groupCollection := s.Database.Collection("Groups")
+		cursor, err := groupCollection.Find(ctx, notDeleted(bson.M{"_id": bson.M{"$in": user.GroupIDs}}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch groups: %w", err)
+		}
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			var g models.Group
+			if err := cursor.Decode(&g); err != nil {
+				return nil, fmt.Errorf("failed to decode group: %w", err)
+			}
+			permissionIDs = append(permissionIDs, g.PermissionIDs...)
+		}
+	}
+
+	if len(permissionIDs) == 0 {
+		return []string{}, nil
+	}
+
+	permissionCollection := s.Database.Collection("Permissions")
+	cursor, err := permissionCollection.Find(ctx, notDeleted(bson.M{"_id": bson.M{"$in": permissionIDs}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch permissions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var permissions []string
+	for cursor.Next(ctx) {
+		var p models.Permission
+		if err := cursor.Decode(&p); err != nil {
+			return nil, fmt.Errorf("failed to decode permission: %w", err)
+		}
+		permissions = append(permissions, p.Codename)
+	}
+
+	return utils.UniqueSlice(permissions), nil
+}
+
+// ExplainEffectivePermissions returns the same permissions as GetEffectivePermissions, each annotated with
+// whether it is granted directly or by which of the user's groups
+func (s *UserService) ExplainEffectivePermissions(ctx context.Context, userID string) (*models.EffectivePermissions, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	sources := make(map[primitive.ObjectID][]models.PermissionSource)
+	for _, permissionID := range user.PermissionIDs {
+		sources[permissionID] = append(sources[permissionID], models.PermissionSource{Type: models.PermissionSourceDirect})
+	}
+
+	if len(user.GroupIDs) > 0 {
+		cursor, err := HandlerGroupService.Collection.Find(ctx, notDeleted(bson.M{"_id": bson.M{"$in": user.GroupIDs}}),
+			options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch groups: %w", err)
+		}
+		var groups []models.Group
+		if err := cursor.All(ctx, &groups); err != nil {
+			return nil, fmt.Errorf("failed to decode groups: %w", err)
+		}
+
+		for _, group := range groups {
+			for _, permissionID := range group.PermissionIDs {
+				sources[permissionID] = append(sources[permissionID], models.PermissionSource{
+					Type:      models.PermissionSourceGroup,
+					GroupID:   &group.ID,
+					GroupName: group.Name,
+				})
+			}
+		}
+	}
+
+	explained := &models.EffectivePermissions{
+		UserID:      user.ID,
+		IsActive:    user.IsActive,
+		IsSuperuser: user.IsSuperuser,
+		Permissions: []models.EffectivePermission{},
+	}
+	if len(sources) == 0 {
+		return explained, nil
+	}
+
+	permissionIDs := make([]primitive.ObjectID, 0, len(sources))
+	for permissionID := range sources {
+		permissionIDs = append(permissionIDs, permissionID)
+	}
+
+	// ids of deleted permissions find nothing and drop out like in GetEffectivePermissions
+	cursor, err := HandlerPermissionService.Collection.Find(ctx, notDeleted(bson.M{"_id": bson.M{"$in": permissionIDs}}),
+		options.Find().SetSort(bson.D{{Key: "codename", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch permissions: %w", err)
+	}
+	var permissions []models.PermissionGet
+	if err := cursor.All(ctx, &permissions); err != nil {
+		return nil, fmt.Errorf("failed to decode permissions: %w", err)
+	}
+
+	for _, permission := range permissions {
+		explained.Permissions = append(explained.Permissions, models.EffectivePermission{
+			PermissionGet: permission,
+			Sources:       sources[permission.ID],
+		})
+	}
+	return explained, nil
+}
@@ -18,6 +18,7 @@ package django_auth
 
 import (
 	"github.com/bushubdegefu/m-playground/django-auth/controllers"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
 	"github.com/bushubdegefu/m-playground/logs"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -31,7 +32,7 @@ func SetupRoutes(app *echo.Echo) {
 		Format: `{"time":"${time_rfc3339_nano}","id":"${id}","remote_ip":"${remote_ip}",` +
 			`"host":"${host}","method":"${method}","uri":"${uri}","user_agent":"${user_agent}",` +
 			`"status":${status},"error":"${error}","latency":${latency},"latency_human":"${latency_human}"` +
-			`,"bytes_in":${bytes_in},"bytes_out":${bytes_out}}` + "\n",
+			`,"bytes_in":${bytes_in},"bytes_out":${bytes_out},"impersonated_by":"${header:` + utils.ImpersonatorHeader + `}"}` + "\n",
 		Output: logOutput,
 	}))
 
@@ -43,6 +44,50 @@ func SetupRoutes(app *echo.Echo) {
 
 	// db session injection
 	gapp.Use(dbsessioninjection)
+	gapp.POST("/login", controllers.Login).Name = "django_auth_login"
+	gapp.POST("/login/mfa", controllers.LoginMFA).Name = "django_auth_login"
+	gapp.POST("/login/mfa/enroll", controllers.LoginMFAEnroll).Name = "django_auth_login"
+	gapp.POST("/login/mfa/confirm", controllers.LoginMFAConfirm).Name = "django_auth_login"
+	gapp.POST("/login/unlock", controllers.UnlockLogin).Name = "django_auth_can_change_user"
+	gapp.POST("/refresh", controllers.RefreshToken).Name = "django_auth_refresh"
+	gapp.POST("/password/reset", controllers.RequestPasswordReset).Name = "django_auth_password_reset"
+	gapp.POST("/password/reset/confirm", controllers.ConfirmPasswordReset).Name = "django_auth_password_reset"
+	gapp.POST("/register", controllers.Register).Name = "django_auth_register"
+	gapp.POST("/register/verify", controllers.VerifyEmail).Name = "django_auth_register"
+	gapp.POST("/register/resend", controllers.ResendVerification).Name = "django_auth_register"
+	gapp.POST("/logout", controllers.Logout).Name = "django_auth_logout"
+	gapp.GET("/session", controllers.GetSession).Name = "django_auth_session"
+	gapp.POST("/user/:user_id/revoke-sessions", controllers.RevokeUserSessions).Name = "django_auth_can_change_user"
+	gapp.POST("/user/:user_id/impersonate", controllers.ImpersonateUser).Name = "django_auth_can_impersonate_user"
+	gapp.POST("/authz/check", controllers.CheckAuthorization).Name = "django_auth_can_check_authorization"
+
+	gapp.POST("/me/mfa/enroll", controllers.EnrollMyMFA).Name = "django_auth_manage_own_mfa"
+	gapp.POST("/me/mfa/confirm", controllers.ConfirmMyMFA).Name = "django_auth_manage_own_mfa"
+	gapp.DELETE("/user/:user_id/mfa", controllers.ResetUserMFA).Name = "django_auth_can_change_user"
+
+	gapp.POST("/me/token", controllers.PostMyAccessToken).Name = "django_auth_manage_own_token"
+	gapp.GET("/me/token", controllers.GetMyAccessTokens).Name = "django_auth_manage_own_token"
+	gapp.DELETE("/me/token/:token_id", controllers.DeleteMyAccessToken).Name = "django_auth_manage_own_token"
+	gapp.GET("/accesstoken", controllers.GetAccessTokens).Name = "django_auth_can_view_personalaccesstoken"
+	gapp.DELETE("/accesstoken/:token_id", controllers.DeleteAccessToken).Name = "django_auth_can_delete_personalaccesstoken"
+
+	gapp.GET("/.well-known/openid-configuration", controllers.OpenIDConfiguration).Name = "django_auth_oidc"
+	gapp.GET("/oidc/jwks", controllers.JSONWebKeySet).Name = "django_auth_oidc"
+	gapp.GET("/oidc/authorize", controllers.Authorize).Name = "django_auth_oidc"
+	gapp.POST("/oidc/authorize", controllers.AuthorizeWithToken).Name = "django_auth_oidc"
+	gapp.POST("/oidc/token", controllers.Token).Name = "django_auth_oidc"
+	gapp.GET("/oidc/userinfo", controllers.UserInfo).Name = "django_auth_oidc"
+	gapp.POST("/oidc/userinfo", controllers.UserInfo).Name = "django_auth_oidc"
+
+	gapp.GET("/signingkey", controllers.GetSigningKeys).Name = "django_auth_can_view_signingkey"
+	gapp.POST("/signingkey/rotate", controllers.RotateSigningKeys).Name = "django_auth_can_rotate_signingkey"
+
+	gapp.GET("/oidcclient", controllers.GetOIDCClients).Name = "django_auth_can_view_oidcclient"
+	gapp.GET("/oidcclient/:oidcclient_id", controllers.GetOIDCClientByID).Name = "django_auth_can_view_oidcclient"
+	gapp.POST("/oidcclient", controllers.PostOIDCClient).Name = "django_auth_can_add_oidcclient"
+	gapp.PATCH("/oidcclient/:oidcclient_id", controllers.PatchOIDCClient).Name = "django_auth_can_change_oidcclient"
+	gapp.DELETE("/oidcclient/:oidcclient_id", controllers.DeleteOIDCClient).Name = "django_auth_can_delete_oidcclient"
+
 	gapp.GET("/user", controllers.GetUsers).Name = "django_auth_can_view_user"
 	gapp.GET("/user/:user_id", controllers.GetUserByID).Name = "django_auth_can_view_user"
 	gapp.POST("/user", controllers.PostUser).Name = "django_auth_can_add_user"
@@ -54,6 +99,7 @@ func SetupRoutes(app *echo.Echo) {
 	gapp.GET("/userpermission/:user_id", controllers.GetPermissionsOfUsers).Name = "django_auth_can_view_permission"
 	gapp.GET("/permissionnoncomplementuser/:user_id", controllers.GetAllPermissionsOfUsers).Name = "django_auth_can_view_permissioncomplement"
 	gapp.GET("/permissioncomplementuser/:user_id", controllers.GetPermissionComplementUsers).Name = "django_auth_can_view_permissioncomplement"
+	gapp.GET("/user/:user_id/effective-permissions", controllers.GetEffectivePermissionsOfUser).Name = "django_auth_can_view_permission"
 
 	gapp.POST("/usergroup/:group_id/:user_id", controllers.AddGroupToUser).Name = "django_auth_can_add_group"
 	gapp.DELETE("/usergroup/:group_id/:user_id", controllers.DeleteGroupFromUser).Name = "django_auth_can_delete_group"
@@ -79,4 +125,22 @@ func SetupRoutes(app *echo.Echo) {
 	gapp.PATCH("/permission/:permission_id", controllers.PatchPermission).Name = "django_auth_can_change_permission"
 	gapp.DELETE("/permission/:permission_id", controllers.DeletePermission).Name = "django_auth_can_delete_permission"
 
+	gapp.GET("/integrity/orphans", controllers.GetOrphans).Name = "django_auth_can_view_orphans"
+	gapp.POST("/integrity/orphans/repair", controllers.RepairOrphans).Name = "django_auth_can_repair_orphans"
+
+	gapp.GET("/trash/user", controllers.GetUserTrash).Name = "django_auth_can_view_trash"
+	gapp.GET("/trash/group", controllers.GetGroupTrash).Name = "django_auth_can_view_trash"
+	gapp.GET("/trash/permission", controllers.GetPermissionTrash).Name = "django_auth_can_view_trash"
+	gapp.POST("/trash/user/:user_id/restore", controllers.RestoreUser).Name = "django_auth_can_restore_user"
+	gapp.POST("/trash/group/:group_id/restore", controllers.RestoreGroup).Name = "django_auth_can_restore_group"
+	gapp.POST("/trash/permission/:permission_id/restore", controllers.RestorePermission).Name = "django_auth_can_restore_permission"
+
+	gapp.GET("/audit", controllers.GetAuditLogs).Name = "django_auth_can_view_auditlog"
+	gapp.GET("/audit/:audit_id", controllers.GetAuditLogByID).Name = "django_auth_can_view_auditlog"
+
+	gapp.GET("/objectpermission", controllers.GetObjectPermissions).Name = "django_auth_can_view_objectpermission"
+	gapp.GET("/objectpermission/:objectpermission_id", controllers.GetObjectPermissionByID).Name = "django_auth_can_view_objectpermission"
+	gapp.POST("/objectpermission", controllers.PostObjectPermission).Name = "django_auth_can_add_objectpermission"
+	gapp.DELETE("/objectpermission/:objectpermission_id", controllers.DeleteObjectPermission).Name = "django_auth_can_delete_objectpermission"
+
 }
@@ -0,0 +1,321 @@
+package utils
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"golang.org/x/crypto/argon2"
+)
+
+// PasswordHasher hashes and verifies passwords stored in Django's algorithm$...$hash format
+type PasswordHasher interface {
+	// Algorithm is the prefix stored in front of the encoded hash
+	Algorithm() string
+	// Encode hashes the password with the given salt
+	Encode(password, salt string) (string, error)
+	// Verify checks the password against the encoded hash in constant time
+	Verify(password, encoded string) bool
+	// MustUpdate reports whether the encoded hash uses outdated parameters
+	MustUpdate(encoded string) bool
+}
+
+var (
+	hashersMu       sync.RWMutex
+	passwordHashers = map[string]PasswordHasher{}
+)
+
+func init() {
+	RegisterPasswordHasher(&PBKDF2SHA256Hasher{})
+	RegisterPasswordHasher(&Argon2Hasher{})
+	RegisterPasswordHasher(&LegacySHA512Hasher{})
+}
+
+// RegisterPasswordHasher makes a hasher available under its algorithm name
+func RegisterPasswordHasher(hasher PasswordHasher) {
+	hashersMu.Lock()
+	defer hashersMu.Unlock()
+	passwordHashers[hasher.Algorithm()] = hasher
+}
+
+// GetPasswordHasher returns the registered hasher for the algorithm
+func GetPasswordHasher(algorithm string) (PasswordHasher, error) {
+	hashersMu.RLock()
+	defer hashersMu.RUnlock()
+	hasher, ok := passwordHashers[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unknown password hashing algorithm: %s", algorithm)
+	}
+	return hasher, nil
+}
+
+// DefaultPasswordHasher returns the first hasher listed in PASSWORD_HASHERS, same as Django
+func DefaultPasswordHasher() (PasswordHasher, error) {
+	algorithms := strings.Split(configs.AppConfig.GetOrDefault("PASSWORD_HASHERS", "pbkdf2_sha256,argon2,sha512_legacy"), ",")
+	return GetPasswordHasher(strings.TrimSpace(algorithms[0]))
+}
+
+// identifies the hasher from the encoded hash, legacy hashes carry no algorithm prefix
+func identifyHasher(encoded string) (PasswordHasher, error) {
+	algorithm, _, found := strings.Cut(encoded, "$")
+	if !found {
+		algorithm = legacyAlgorithm
+	}
+	return GetPasswordHasher(algorithm)
+}
+
+// MakePassword hashes the password with the default hasher and a fresh random salt
+func MakePassword(password string) (string, error) {
+	hasher, err := DefaultPasswordHasher()
+	if err != nil {
+		return "", err
+	}
+
+	salt, err := randomSalt(22)
+	if err != nil {
+		return "", err
+	}
+
+	return hasher.Encode(password, salt)
+}
+
+// UnusablePasswordPrefix marks users that can not log in with a local password, same as Django
+const UnusablePasswordPrefix = "!"
+
+// MakeUnusablePassword returns a value CheckPassword never accepts, set on users of external backends
+func MakeUnusablePassword() (string, error) {
+	suffix, err := randomSalt(40)
+	if err != nil {
+		return "", err
+	}
+	return UnusablePasswordPrefix + suffix, nil
+}
+
+// HasUsablePassword reports whether a local password was ever set for the user
+func HasUsablePassword(encoded string) bool {
+	return encoded != "" && !strings.HasPrefix(encoded, UnusablePasswordPrefix)
+}
+
+// CheckPassword verifies the password and reports whether the stored hash should be upgraded
+func CheckPassword(password, encoded string) (bool, bool) {
+	if !HasUsablePassword(encoded) {
+		return false, false
+	}
+
+	hasher, err := identifyHasher(encoded)
+	if err != nil {
+		return false, false
+	}
+
+	if !hasher.Verify(password, encoded) {
+		return false, false
+	}
+
+	defaultHasher, err := DefaultPasswordHasher()
+	if err != nil {
+		return true, false
+	}
+
+	mustUpdate := hasher.Algorithm() != defaultHasher.Algorithm() || hasher.MustUpdate(encoded)
+	return true, mustUpdate
+}
+
+// random alphanumeric salt like Django's get_random_string
+func randomSalt(length int) (string, error) {
+	const allowedChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	buf := make([]byte, length)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate salt failed: %w", err)
+	}
+	for i, b := range buf {
+		buf[i] = allowedChars[int(b)%len(allowedChars)]
+	}
+	return string(buf), nil
+}
+
+// ##########################################################
+// ##########  PBKDF2 SHA256
+// ##########################################################
+
+// PBKDF2SHA256Hasher stores pbkdf2_sha256$iterations$salt$hash
+type PBKDF2SHA256Hasher struct{}
+
+func (h *PBKDF2SHA256Hasher) Algorithm() string {
+	return "pbkdf2_sha256"
+}
+
+func (h *PBKDF2SHA256Hasher) iterations() int {
+	iterations, err := strconv.Atoi(configs.AppConfig.GetOrDefault("PBKDF2_ITERATIONS", "1000000"))
+	if err != nil || iterations <= 0 {
+		return 1000000
+	}
+	return iterations
+}
+
+func (h *PBKDF2SHA256Hasher) encode(password, salt string, iterations int) (string, error) {
+	if strings.Contains(salt, "$") {
+		return "", errors.New("salt must not contain $")
+	}
+
+	hash, err := pbkdf2.Key(sha256.New, password, []byte(salt), iterations, sha256.Size)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s$%d$%s$%s", h.Algorithm(), iterations, salt, base64.StdEncoding.EncodeToString(hash)), nil
+}
+
+func (h *PBKDF2SHA256Hasher) decode(encoded string) (int, string, bool) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != h.Algorithm() {
+		return 0, "", false
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return 0, "", false
+	}
+	return iterations, parts[2], true
+}
+
+func (h *PBKDF2SHA256Hasher) Encode(password, salt string) (string, error) {
+	return h.encode(password, salt, h.iterations())
+}
+
+func (h *PBKDF2SHA256Hasher) Verify(password, encoded string) bool {
+	iterations, salt, ok := h.decode(encoded)
+	if !ok {
+		return false
+	}
+
+	candidate, err := h.encode(password, salt, iterations)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(candidate), []byte(encoded)) == 1
+}
+
+func (h *PBKDF2SHA256Hasher) MustUpdate(encoded string) bool {
+	iterations, _, ok := h.decode(encoded)
+	return !ok || iterations != h.iterations()
+}
+
+// ##########################################################
+// ##########  Argon2id
+// ##########################################################
+
+// Argon2Hasher stores argon2$argon2id$v=19$m=memory,t=time,p=threads$salt$hash
+type Argon2Hasher struct{}
+
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+func (h *Argon2Hasher) Algorithm() string {
+	return "argon2"
+}
+
+func (h *Argon2Hasher) params() argon2Params {
+	memory, _ := strconv.ParseUint(configs.AppConfig.GetOrDefault("ARGON2_MEMORY_COST", "102400"), 10, 32)
+	time, _ := strconv.ParseUint(configs.AppConfig.GetOrDefault("ARGON2_TIME_COST", "2"), 10, 32)
+	threads, _ := strconv.ParseUint(configs.AppConfig.GetOrDefault("ARGON2_PARALLELISM", "8"), 10, 8)
+	return argon2Params{memory: uint32(memory), time: uint32(time), threads: uint8(threads)}
+}
+
+func (h *Argon2Hasher) encode(password string, salt []byte, params argon2Params, keyLen uint32) string {
+	hash := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, keyLen)
+	return fmt.Sprintf("%s$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		h.Algorithm(), argon2.Version, params.memory, params.time, params.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(hash))
+}
+
+func (h *Argon2Hasher) decode(encoded string) (argon2Params, []byte, []byte, bool) {
+	var params argon2Params
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != h.Algorithm() || parts[1] != "argon2id" {
+		return params, nil, nil, false
+	}
+
+	if parts[2] != fmt.Sprintf("v=%d", argon2.Version) {
+		return params, nil, nil, false
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, false
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(hash) == 0 {
+		return params, nil, nil, false
+	}
+	return params, salt, hash, true
+}
+
+func (h *Argon2Hasher) Encode(password, salt string) (string, error) {
+	params := h.params()
+	if params.memory == 0 || params.time == 0 || params.threads == 0 {
+		return "", errors.New("invalid argon2 parameters")
+	}
+	return h.encode(password, []byte(salt), params, 32), nil
+}
+
+func (h *Argon2Hasher) Verify(password, encoded string) bool {
+	params, salt, hash, ok := h.decode(encoded)
+	if !ok {
+		return false
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(hash)))
+	return subtle.ConstantTimeCompare(candidate, hash) == 1
+}
+
+func (h *Argon2Hasher) MustUpdate(encoded string) bool {
+	params, _, _, ok := h.decode(encoded)
+	return !ok || params != h.params()
+}
+
+// ##########################################################
+// ##########  Legacy SHA512
+// ##########################################################
+
+const legacyAlgorithm = "sha512_legacy"
+
+// LegacySHA512Hasher verifies the unprefixed sha512(password + SECRETE_SALT) hex hashes
+// written before the hasher registry existed, so those users can still log in and get rehashed
+type LegacySHA512Hasher struct{}
+
+func (h *LegacySHA512Hasher) Algorithm() string {
+	return legacyAlgorithm
+}
+
+func (h *LegacySHA512Hasher) Encode(password, salt string) (string, error) {
+	// the legacy scheme only ever used the global salt
+	hash := sha512.Sum512(append([]byte(password), []byte(configs.AppConfig.Get("SECRETE_SALT"))...))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func (h *LegacySHA512Hasher) Verify(password, encoded string) bool {
+	candidate, _ := h.Encode(password, "")
+	return subtle.ConstantTimeCompare([]byte(candidate), []byte(encoded)) == 1
+}
+
+func (h *LegacySHA512Hasher) MustUpdate(encoded string) bool {
+	return true
+}
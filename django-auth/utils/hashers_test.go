@@ -0,0 +1,98 @@
+package utils
+
+import "testing"
+
+// pbkdf2 vector from Django's own hasher tests, argon2id vector from the argon2 reference implementation
+const (
+	djangoPBKDF2Vector    = "pbkdf2_sha256$12000$seasalt$Ybw8zsFxqja97tY/o6G+Fy1ksY4U/Hw3DRrGED6Up4s="
+	referenceArgon2Vector = "argon2$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+)
+
+func TestPBKDF2SHA256HasherEncode(t *testing.T) {
+	hasher := &PBKDF2SHA256Hasher{}
+
+	encoded, err := hasher.encode("lètmein", "seasalt", 12000)
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+	if encoded != djangoPBKDF2Vector {
+		t.Errorf("encode() = %s, want %s", encoded, djangoPBKDF2Vector)
+	}
+
+	if _, err := hasher.encode("lètmein", "sea$salt", 12000); err == nil {
+		t.Error("encode() accepted a salt containing $")
+	}
+}
+
+func TestArgon2HasherEncode(t *testing.T) {
+	hasher := &Argon2Hasher{}
+
+	encoded := hasher.encode("password", []byte("somesalt"), argon2Params{memory: 65536, time: 2, threads: 1}, 32)
+	if encoded != referenceArgon2Vector {
+		t.Errorf("encode() = %s, want %s", encoded, referenceArgon2Vector)
+	}
+}
+
+func TestPasswordHasherVerify(t *testing.T) {
+	tests := []struct {
+		name     string
+		hasher   PasswordHasher
+		password string
+		encoded  string
+		want     bool
+	}{
+		{"pbkdf2 django vector", &PBKDF2SHA256Hasher{}, "lètmein", djangoPBKDF2Vector, true},
+		{"pbkdf2 wrong password", &PBKDF2SHA256Hasher{}, "letmein", djangoPBKDF2Vector, false},
+		{"pbkdf2 tampered iterations", &PBKDF2SHA256Hasher{}, "lètmein", "pbkdf2_sha256$12001$seasalt$Ybw8zsFxqja97tY/o6G+Fy1ksY4U/Hw3DRrGED6Up4s=", false},
+		{"pbkdf2 malformed", &PBKDF2SHA256Hasher{}, "lètmein", "pbkdf2_sha256$seasalt", false},
+		{"argon2 reference vector", &Argon2Hasher{}, "password", referenceArgon2Vector, true},
+		{"argon2 wrong password", &Argon2Hasher{}, "Password", referenceArgon2Vector, false},
+		{"argon2 other variant", &Argon2Hasher{}, "password", "argon2$argon2i$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc", false},
+		{"argon2 other version", &Argon2Hasher{}, "password", "argon2$argon2id$v=16$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc", false},
+		{"argon2 missing hash", &Argon2Hasher{}, "password", "argon2$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hasher.Verify(tt.password, tt.encoded); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	t.Setenv("PASSWORD_HASHERS", "pbkdf2_sha256,argon2")
+	t.Setenv("PBKDF2_ITERATIONS", "12000")
+
+	tests := []struct {
+		name           string
+		password       string
+		encoded        string
+		wantValid      bool
+		wantMustUpdate bool
+	}{
+		{"default hasher with current iterations", "lètmein", djangoPBKDF2Vector, true, false},
+		{"other hasher is upgraded", "password", referenceArgon2Vector, true, true},
+		{"wrong password", "letmein", djangoPBKDF2Vector, false, false},
+		{"unusable password", "", UnusablePasswordPrefix + "abc", false, false},
+		{"empty hash", "", "", false, false},
+		{"unknown algorithm", "lètmein", "md5$seasalt$abc", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, mustUpdate := CheckPassword(tt.password, tt.encoded)
+			if valid != tt.wantValid || mustUpdate != tt.wantMustUpdate {
+				t.Errorf("CheckPassword() = %v, %v, want %v, %v", valid, mustUpdate, tt.wantValid, tt.wantMustUpdate)
+			}
+		})
+	}
+
+	t.Run("iterations raised", func(t *testing.T) {
+		t.Setenv("PBKDF2_ITERATIONS", "24000")
+		if valid, mustUpdate := CheckPassword("lètmein", djangoPBKDF2Vector); !valid || !mustUpdate {
+			t.Errorf("CheckPassword() = %v, %v, want true, true", valid, mustUpdate)
+		}
+	})
+}
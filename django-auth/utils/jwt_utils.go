@@ -1,10 +1,155 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+const (
+	AccessTokenType  = "access"
+	RefreshTokenType = "refresh"
+
+	// set on the request claim when a personal access token is used instead of a JWT
+	PersonalAccessTokenType = "personal_access"
+
+	// set on the request claim when a cookie session is used instead of a JWT
+	SessionTokenType = "session"
+
+	// short lived token between the password and the second factor step of a login
+	MFATokenType = "mfa"
+
+	MFAStageVerify = "verify"
+	MFAStageEnroll = "enroll"
+
+	// handed to OIDC relying parties, only accepted by the userinfo endpoint
+	OIDCAccessTokenType = "oidc_access"
+)
+
+// request header the auth middleware sets to the superuser's id on impersonated requests, for the access log
+const ImpersonatorHeader = "impersonated-by"
+
+// ActorClaim names the superuser acting as the token's user during an impersonation (RFC 8693 act claim)
+type ActorClaim struct {
+	UserID   string `json:"sub"`
+	Username string `json:"username"`
+}
+
+// UserClaim is the payload carried by access and refresh tokens
+type UserClaim struct {
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	IsSuperuser bool   `json:"is_superuser"`
+	TokenType   string `json:"token_type"`
+	FamilyID    string `json:"family_id,omitempty"`
+	MFAStage    string `json:"mfa_stage,omitempty"`
+	ClientID    string `json:"client_id,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	// set on impersonation tokens, the real user making the requests
+	Act *ActorClaim `json:"act,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// returns the lifetime configured for the token type
+func tokenLifetime(tokenType string) (time.Duration, error) {
+	var minutes string
+	switch tokenType {
+	case AccessTokenType:
+		minutes = configs.AppConfig.GetOrDefault("JWT_ACCESS_TOKEN_MINUTES", "15")
+	case RefreshTokenType:
+		minutes = configs.AppConfig.GetOrDefault("JWT_REFRESH_TOKEN_MINUTES", "10080")
+	case MFATokenType:
+		minutes = configs.AppConfig.GetOrDefault("MFA_TOKEN_MINUTES", "5")
+	case OIDCAccessTokenType:
+		minutes = configs.AppConfig.GetOrDefault("OIDC_TOKEN_MINUTES", "15")
+	default:
+		return 0, fmt.Errorf("unknown token type: %s", tokenType)
+	}
+
+	lifetime, err := strconv.Atoi(minutes)
+	if err != nil {
+		return 0, fmt.Errorf("invalid lifetime for %s token: %w", tokenType, err)
+	}
+
+	return time.Duration(lifetime) * time.Minute, nil
+}
+
+// TokenLifetime returns how long tokens of the given type stay valid
+func TokenLifetime(tokenType string) time.Duration {
+	lifetime, _ := tokenLifetime(tokenType)
+	return lifetime
+}
+
+// ImpersonationLifetime is IMPERSONATION_TOKEN_MINUTES, 15 by default
+func ImpersonationLifetime() time.Duration {
+	minutes, err := strconv.Atoi(configs.AppConfig.GetOrDefault("IMPERSONATION_TOKEN_MINUTES", "15"))
+	if err != nil || minutes <= 0 {
+		minutes = 15
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// IsImpersonated reports whether the claim belongs to a token issued for an impersonation
+func (c *UserClaim) IsImpersonated() bool {
+	return c.Act != nil
+}
+
+// CreateJWTToken fills in the registered claims (jti, expiry ...) and signs the claim as a token of the given type
+func CreateJWTToken(claim *UserClaim, tokenType string) (string, error) {
+	lifetime, err := tokenLifetime(tokenType)
+	if err != nil {
+		return "", err
+	}
+
+	// impersonation tokens stay short lived whatever the token type
+	if claim.Act != nil {
+		lifetime = min(lifetime, ImpersonationLifetime())
+	}
+
+	now := time.Now()
+	jti, _ := uuid.NewV7()
+	claim.TokenType = tokenType
+	claim.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        jti.String(),
+		Issuer:    configs.AppConfig.GetOrDefault("JWT_ISSUER", "django_auth"),
+		Subject:   claim.UserID,
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(lifetime)),
+	}
+
+	return signWithActiveKey(claim)
+}
+
+// ParseJWTToken verifies the token signature against the key ring, expiry and type and returns its claim
+func ParseJWTToken(tokenString string, tokenType string) (*UserClaim, error) {
+	if _, err := tokenLifetime(tokenType); err != nil {
+		return nil, err
+	}
+
+	claim := new(UserClaim)
+	_, err := jwt.ParseWithClaims(tokenString, claim, verificationKey,
+		jwt.WithValidMethods(SupportedKeyAlgorithms),
+		jwt.WithIssuer(configs.AppConfig.GetOrDefault("JWT_ISSUER", "django_auth")),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if claim.TokenType != tokenType {
+		return nil, errors.New("token type mismatch")
+	}
+
+	return claim, nil
+}
+
 // Return Unique values in list
 func UniqueSlice(slice []string) []string {
 	keys := make(map[string]bool)
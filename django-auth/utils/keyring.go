@@ -0,0 +1,260 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// key states, a next key is published before it signs so verifiers already know it
+// and a retired key keeps verifying until the tokens it signed have expired
+const (
+	KeyStateNext    = "next"
+	KeyStateActive  = "active"
+	KeyStateRetired = "retired"
+)
+
+// algorithms keys can be generated for
+var SupportedKeyAlgorithms = []string{"RS256", "ES256", "EdDSA"}
+
+var ErrKeyRingEmpty = errors.New("no active signing key is loaded")
+
+// KeyRingEntry is a parsed signing key held in memory
+type KeyRingEntry struct {
+	Kid         string
+	Algorithm   string
+	State       string
+	RetireAfter time.Time
+	Key         crypto.Signer
+}
+
+var keyRing struct {
+	sync.RWMutex
+	entries []KeyRingEntry
+}
+
+// SetKeyRing replaces the in memory keys, retired keys past their retire after date are dropped
+func SetKeyRing(entries []KeyRingEntry) {
+	now := time.Now()
+	loaded := make([]KeyRingEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.State == KeyStateRetired && now.After(entry.RetireAfter) {
+			continue
+		}
+		loaded = append(loaded, entry)
+	}
+
+	keyRing.Lock()
+	defer keyRing.Unlock()
+	keyRing.entries = loaded
+}
+
+func signingMethod(algorithm string) (jwt.SigningMethod, error) {
+	switch algorithm {
+	case "RS256":
+		return jwt.SigningMethodRS256, nil
+	case "ES256":
+		return jwt.SigningMethodES256, nil
+	case "EdDSA":
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+}
+
+// GenerateSigningKey creates a private key for the algorithm
+func GenerateSigningKey(algorithm string) (crypto.Signer, error) {
+	switch algorithm {
+	case "RS256":
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case "ES256":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "EdDSA":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+}
+
+// KeyAlgorithm returns the JWS algorithm a parsed key signs with
+func KeyAlgorithm(key crypto.Signer) (string, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return "RS256", nil
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return "", errors.New("only P-256 ecdsa keys are supported")
+		}
+		return "ES256", nil
+	case ed25519.PrivateKey:
+		return "EdDSA", nil
+	default:
+		return "", fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+// EncodePrivateKey encodes the key as a PKCS8 PEM block
+func EncodePrivateKey(key crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", fmt.Errorf("encode signing key failed: %w", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// DecodePrivateKey parses a PKCS8 or PKCS1 PEM private key
+func DecodePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("signing key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse signing key failed: %w", err)
+	}
+	key, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+	return key, nil
+}
+
+// publicJWK returns the public part of the key as a JWK
+func publicJWK(key crypto.Signer, algorithm, kid string) (JSONWebKey, error) {
+	jwk := JSONWebKey{Use: "sig", Alg: algorithm, Kid: kid}
+	switch pub := key.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		ecdhKey, err := pub.ECDH()
+		if err != nil {
+			return JSONWebKey{}, err
+		}
+		// uncompressed point, 0x04 || X || Y
+		point := ecdhKey.Bytes()
+		size := (len(point) - 1) / 2
+		jwk.Kty = "EC"
+		jwk.Crv = "P-256"
+		jwk.X = base64.RawURLEncoding.EncodeToString(point[1 : 1+size])
+		jwk.Y = base64.RawURLEncoding.EncodeToString(point[1+size:])
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	default:
+		return JSONWebKey{}, fmt.Errorf("unsupported key type %T", pub)
+	}
+	return jwk, nil
+}
+
+// KeyID is the RFC 7638 thumbprint of the public key
+func KeyID(key crypto.Signer) (string, error) {
+	jwk, err := publicJWK(key, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	var members string
+	switch jwk.Kty {
+	case "RSA":
+		members = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.E, jwk.N)
+	case "EC":
+		members = fmt.Sprintf(`{"crv":"%s","kty":"EC","x":"%s","y":"%s"}`, jwk.Crv, jwk.X, jwk.Y)
+	case "OKP":
+		members = fmt.Sprintf(`{"crv":"%s","kty":"OKP","x":"%s"}`, jwk.Crv, jwk.X)
+	}
+	sum := sha256.Sum256([]byte(members))
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// signs the claims with the active key, the kid header tells verifiers which key to use
+func signWithActiveKey(claims jwt.Claims) (string, error) {
+	keyRing.RLock()
+	defer keyRing.RUnlock()
+
+	for _, entry := range keyRing.entries {
+		if entry.State != KeyStateActive {
+			continue
+		}
+		method, err := signingMethod(entry.Algorithm)
+		if err != nil {
+			return "", err
+		}
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = entry.Kid
+		return token.SignedString(entry.Key)
+	}
+	return "", ErrKeyRingEmpty
+}
+
+// jwt.Keyfunc resolving the kid header, retired keys still verify until their retire after date
+func verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	keyRing.RLock()
+	defer keyRing.RUnlock()
+
+	for _, entry := range keyRing.entries {
+		if entry.Kid != kid {
+			continue
+		}
+		if entry.State == KeyStateRetired && time.Now().After(entry.RetireAfter) {
+			return nil, errors.New("token was signed with an expired key")
+		}
+		if token.Method.Alg() != entry.Algorithm {
+			return nil, errors.New("token algorithm does not match the signing key")
+		}
+		return entry.Key.Public(), nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// KeyRingJWKS returns every key that may have signed, or is about to sign, a live token
+func KeyRingJWKS() (JSONWebKeySet, error) {
+	keyRing.RLock()
+	defer keyRing.RUnlock()
+
+	keySet := JSONWebKeySet{Keys: []JSONWebKey{}}
+	now := time.Now()
+	for _, entry := range keyRing.entries {
+		if entry.State == KeyStateRetired && now.After(entry.RetireAfter) {
+			continue
+		}
+		jwk, err := publicJWK(entry.Key, entry.Algorithm, entry.Kid)
+		if err != nil {
+			return JSONWebKeySet{}, err
+		}
+		keySet.Keys = append(keySet.Keys, jwk)
+	}
+	return keySet, nil
+}
+
+// MaxTokenLifetime is the longest any signed token stays valid, a retired key is kept at least this long
+func MaxTokenLifetime() time.Duration {
+	var longest time.Duration
+	for _, tokenType := range []string{AccessTokenType, RefreshTokenType, MFATokenType, OIDCAccessTokenType} {
+		longest = max(longest, TokenLifetime(tokenType))
+	}
+	return longest
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JSONWebKey is the public part of a signing key as published on the JWKS endpoint
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet is the body of the JWKS endpoint
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// OIDCIssuer is the issuer of id tokens, the discovery document is served below it
+func OIDCIssuer() string {
+	return configs.AppConfig.GetOrDefault("OIDC_ISSUER",
+		"http://localhost:"+configs.AppConfig.GetOrDefault("HTTP_PORT", "8080")+"/api/v1/django_auth")
+}
+
+// SignIDToken signs the id token claims with the active key of the key ring
+func SignIDToken(claims jwt.MapClaims) (string, error) {
+	return signWithActiveKey(claims)
+}
+
+// VerifyPKCE checks the code_verifier against the code_challenge, only S256 is supported
+func VerifyPKCE(verifier, challenge, method string) bool {
+	if method != "S256" || len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyPKCE(t *testing.T) {
+	// the example from RFC 7636 appendix B
+	const (
+		verifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+		challenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	)
+
+	tests := []struct {
+		name      string
+		verifier  string
+		challenge string
+		method    string
+		want      bool
+	}{
+		{"rfc 7636 example", verifier, challenge, "S256", true},
+		{"plain method is not supported", verifier, verifier, "plain", false},
+		{"missing method", verifier, challenge, "", false},
+		{"lowercase method", verifier, challenge, "s256", false},
+		{"other verifier", strings.Replace(verifier, "d", "e", 1), challenge, "S256", false},
+		{"padded challenge", verifier, challenge + "=", "S256", false},
+		{"verifier shorter than 43 characters", verifier[:42], challenge, "S256", false},
+		{"verifier longer than 128 characters", strings.Repeat("a", 129), challenge, "S256", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyPKCE(tt.verifier, tt.challenge, tt.method); got != tt.want {
+				t.Errorf("VerifyPKCE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
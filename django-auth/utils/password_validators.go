@@ -0,0 +1,305 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/configs"
+)
+
+// passwords from the zxcvbn frequency lists (MIT license), lower cased, one per line
+//
+//go:embed common-passwords.txt.gz
+var embeddedCommonPasswords []byte
+
+// PasswordViolation is a single password policy rule the password breaks
+type PasswordViolation struct {
+	Code    string
+	Message string
+}
+
+// PasswordPolicyError lists every password policy rule the password breaks
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return strings.Join(messages, " ")
+}
+
+// FieldErrors reports the violations against the request field holding the password
+func (e *PasswordPolicyError) FieldErrors(field string) []common.FieldError {
+	fieldErrors := make([]common.FieldError, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		fieldErrors = append(fieldErrors, common.FieldError{
+			Field:   field,
+			Code:    violation.Code,
+			Message: violation.Message,
+		})
+	}
+	return fieldErrors
+}
+
+// a validator gets the password and the user attributes (username, email, ...) it must not resemble
+type passwordValidator func(password string, attributes map[string]string) *PasswordViolation
+
+// validators by the name used in PASSWORD_VALIDATORS, same rules as Django's AUTH_PASSWORD_VALIDATORS
+var passwordValidators = map[string]passwordValidator{
+	"minimum_length":            minimumLengthValidator,
+	"user_attribute_similarity": userAttributeSimilarityValidator,
+	"common_password":           commonPasswordValidator,
+	"numeric":                   numericPasswordValidator,
+	"character_classes":         characterClassValidator,
+}
+
+const defaultPasswordValidators = "minimum_length,user_attribute_similarity,common_password,numeric,character_classes"
+
+// ValidatePassword runs the validators listed in PASSWORD_VALIDATORS and returns a *PasswordPolicyError
+// with every violation, attributes are the user fields the password is compared against
+func ValidatePassword(password string, attributes map[string]string) error {
+	names := strings.Split(configs.AppConfig.GetOrDefault("PASSWORD_VALIDATORS", defaultPasswordValidators), ",")
+
+	var violations []PasswordViolation
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		validator, ok := passwordValidators[name]
+		if !ok {
+			return fmt.Errorf("unknown password validator: %s", name)
+		}
+		if violation := validator(password, attributes); violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func passwordConfigInt(key string, fallback int) int {
+	value, err := strconv.Atoi(configs.AppConfig.GetOrDefault(key, strconv.Itoa(fallback)))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+// PASSWORD_MIN_LENGTH characters, 8 by default
+func minimumLengthValidator(password string, attributes map[string]string) *PasswordViolation {
+	minLength := passwordConfigInt("PASSWORD_MIN_LENGTH", 8)
+	if utf8.RuneCountInString(password) < minLength {
+		return &PasswordViolation{
+			Code:    "password_too_short",
+			Message: fmt.Sprintf("This password is too short. It must contain at least %d characters.", minLength),
+		}
+	}
+	return nil
+}
+
+var nonWordPattern = regexp.MustCompile(`\W+`)
+
+// difflib's quick_ratio, the share of characters both strings have in common
+func quickRatio(a, b string) float64 {
+	available := make(map[rune]int)
+	for _, r := range b {
+		available[r]++
+	}
+
+	matches := 0
+	for _, r := range a {
+		if available[r] > 0 {
+			available[r]--
+			matches++
+		}
+	}
+
+	total := utf8.RuneCountInString(a) + utf8.RuneCountInString(b)
+	if total == 0 {
+		return 1
+	}
+	return 2 * float64(matches) / float64(total)
+}
+
+// a password much longer than the attribute can not reach the similarity, no need to compare
+func exceedsMaximumLengthRatio(password string, maxSimilarity float64, value string) bool {
+	passwordLength := float64(utf8.RuneCountInString(password))
+	valueLength := float64(utf8.RuneCountInString(value))
+	return passwordLength >= 10*valueLength && valueLength < maxSimilarity/2*passwordLength
+}
+
+// similarity to the attributes and their parts (split on non word characters) below PASSWORD_MAX_SIMILARITY, 0.7 by default
+func userAttributeSimilarityValidator(password string, attributes map[string]string) *PasswordViolation {
+	maxSimilarity, err := strconv.ParseFloat(configs.AppConfig.GetOrDefault("PASSWORD_MAX_SIMILARITY", "0.7"), 64)
+	if err != nil || maxSimilarity < 0.1 {
+		maxSimilarity = 0.7
+	}
+
+	password = strings.ToLower(password)
+
+	// checking in a fixed order so the reported attribute does not change between requests
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		value := strings.ToLower(attributes[name])
+		if value == "" {
+			continue
+		}
+
+		parts := append(nonWordPattern.Split(value, -1), value)
+		for _, part := range parts {
+			if part == "" || exceedsMaximumLengthRatio(password, maxSimilarity, part) {
+				continue
+			}
+			if quickRatio(password, part) >= maxSimilarity {
+				return &PasswordViolation{
+					Code:    "password_too_similar",
+					Message: fmt.Sprintf("The password is too similar to the %s.", strings.ReplaceAll(name, "_", " ")),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+var commonPasswords struct {
+	once      sync.Once
+	passwords map[string]bool
+	err       error
+}
+
+// the embedded list unless PASSWORD_COMMON_PASSWORDS_FILE points to another one, gzip or plain text
+func loadCommonPasswords() (map[string]bool, error) {
+	commonPasswords.once.Do(func() {
+		data := embeddedCommonPasswords
+		if path := configs.AppConfig.GetOrDefault("PASSWORD_COMMON_PASSWORDS_FILE", ""); path != "" {
+			data, commonPasswords.err = os.ReadFile(path)
+			if commonPasswords.err != nil {
+				return
+			}
+		}
+
+		var reader io.Reader = bytes.NewReader(data)
+		if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+			gzipReader, err := gzip.NewReader(reader)
+			if err != nil {
+				commonPasswords.err = err
+				return
+			}
+			defer gzipReader.Close()
+			reader = gzipReader
+		}
+
+		passwords := make(map[string]bool)
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			if line := strings.ToLower(strings.TrimSpace(scanner.Text())); line != "" {
+				passwords[line] = true
+			}
+		}
+		commonPasswords.passwords, commonPasswords.err = passwords, scanner.Err()
+	})
+	return commonPasswords.passwords, commonPasswords.err
+}
+
+// not on the common password list
+func commonPasswordValidator(password string, attributes map[string]string) *PasswordViolation {
+	passwords, err := loadCommonPasswords()
+	if err != nil {
+		return &PasswordViolation{
+			Code:    "password_list_unavailable",
+			Message: "The common password list could not be loaded.",
+		}
+	}
+
+	if passwords[strings.ToLower(strings.TrimSpace(password))] {
+		return &PasswordViolation{
+			Code:    "password_too_common",
+			Message: "This password is too common.",
+		}
+	}
+	return nil
+}
+
+// not made of digits only
+func numericPasswordValidator(password string, attributes map[string]string) *PasswordViolation {
+	if password != "" && strings.IndexFunc(password, func(r rune) bool { return !unicode.IsDigit(r) }) == -1 {
+		return &PasswordViolation{
+			Code:    "password_entirely_numeric",
+			Message: "This password is entirely numeric.",
+		}
+	}
+	return nil
+}
+
+// character classes the character class policy knows about
+var passwordCharacterClasses = []struct {
+	name        string
+	description string
+	matches     func(rune) bool
+}{
+	{"lower", "a lowercase letter", unicode.IsLower},
+	{"upper", "an uppercase letter", unicode.IsUpper},
+	{"digit", "a digit", unicode.IsDigit},
+	{"symbol", "a symbol", func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }},
+}
+
+// every class in PASSWORD_REQUIRED_CHARACTER_CLASSES (lower, upper, digit, symbol) and at least
+// PASSWORD_MIN_CHARACTER_CLASSES different classes, nothing is required by default
+func characterClassValidator(password string, attributes map[string]string) *PasswordViolation {
+	required := strings.Split(configs.AppConfig.GetOrDefault("PASSWORD_REQUIRED_CHARACTER_CLASSES", ""), ",")
+	minClasses := passwordConfigInt("PASSWORD_MIN_CHARACTER_CLASSES", 0)
+
+	used := 0
+	var missing []string
+	for _, class := range passwordCharacterClasses {
+		if strings.IndexFunc(password, class.matches) != -1 {
+			used++
+			continue
+		}
+		for _, name := range required {
+			if strings.TrimSpace(name) == class.name {
+				missing = append(missing, class.description)
+			}
+		}
+	}
+
+	switch {
+	case len(missing) > 0:
+		return &PasswordViolation{
+			Code:    "password_missing_character_class",
+			Message: fmt.Sprintf("This password must contain %s.", strings.Join(missing, ", ")),
+		}
+	case used < minClasses:
+		return &PasswordViolation{
+			Code: "password_too_few_character_classes",
+			Message: fmt.Sprintf("This password must use at least %d of lowercase letters, uppercase letters, digits and symbols.",
+				minClasses),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func violationCodes(err error) []string {
+	var policyErr *PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return nil
+	}
+	codes := make([]string, 0, len(policyErr.Violations))
+	for _, violation := range policyErr.Violations {
+		codes = append(codes, violation.Code)
+	}
+	return codes
+}
+
+func TestValidatePassword(t *testing.T) {
+	attributes := map[string]string{
+		"username":   "alice_smith",
+		"first_name": "Alice",
+		"email":      "alice.smith@example.com",
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		settings map[string]string
+		want     []string
+	}{
+		{name: "strong password", password: "Correct-Horse-Battery-9"},
+		{name: "too short", password: "x7#q", want: []string{"password_too_short"}},
+		{name: "length counts characters not bytes", password: "ééééééé", want: []string{"password_too_short"}},
+		{name: "common", password: "password", want: []string{"password_too_common"}},
+		{name: "common ignores case", password: "PassWord", want: []string{"password_too_common"}},
+		{name: "common and numeric", password: "12345678", want: []string{"password_too_common", "password_entirely_numeric"}},
+		{name: "numeric", password: "93847261509", want: []string{"password_entirely_numeric"}},
+		{name: "similar to the username", password: "alicesmith", want: []string{"password_too_similar"}},
+		{name: "similar to a part of the email", password: "smith.alice", want: []string{"password_too_similar"}},
+		{
+			name:     "raised minimum length",
+			password: "Correct-Horse",
+			settings: map[string]string{"PASSWORD_MIN_LENGTH": "14"},
+			want:     []string{"password_too_short"},
+		},
+		{
+			name:     "required character classes",
+			password: "correct-horse-battery",
+			settings: map[string]string{"PASSWORD_REQUIRED_CHARACTER_CLASSES": "upper, digit"},
+			want:     []string{"password_missing_character_class"},
+		},
+		{
+			name:     "minimum character classes",
+			password: "correct-horse-battery",
+			settings: map[string]string{"PASSWORD_MIN_CHARACTER_CLASSES": "3"},
+			want:     []string{"password_too_few_character_classes"},
+		},
+		{
+			name:     "minimum character classes met",
+			password: "Correct-horse-battery",
+			settings: map[string]string{"PASSWORD_MIN_CHARACTER_CLASSES": "3"},
+		},
+		{
+			name:     "only the listed validators run",
+			password: "12345678",
+			settings: map[string]string{"PASSWORD_VALIDATORS": "minimum_length, numeric"},
+			want:     []string{"password_entirely_numeric"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.settings {
+				t.Setenv(key, value)
+			}
+
+			err := ValidatePassword(tt.password, attributes)
+			if got := violationCodes(err); !slices.Equal(got, tt.want) {
+				t.Errorf("ValidatePassword(%q) = %v, want violations %v", tt.password, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePasswordUnknownValidator(t *testing.T) {
+	t.Setenv("PASSWORD_VALIDATORS", "minimum_length,nonexistent")
+
+	err := ValidatePassword("Correct-Horse-Battery-9", nil)
+	if err == nil || violationCodes(err) != nil {
+		t.Errorf("ValidatePassword() = %v, want a configuration error", err)
+	}
+}
+
+func TestPasswordPolicyMessages(t *testing.T) {
+	t.Setenv("PASSWORD_REQUIRED_CHARACTER_CLASSES", "upper,digit")
+
+	err := ValidatePassword("alicia99", map[string]string{"first_name": "Alicia", "last_name": "Smith"})
+
+	var policyErr *PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("ValidatePassword() = %v, want a *PasswordPolicyError", err)
+	}
+	want := "The password is too similar to the first name. This password must contain an uppercase letter."
+	if policyErr.Error() != want {
+		t.Errorf("Error() = %q, want %q", policyErr.Error(), want)
+	}
+
+	fieldErrors := policyErr.FieldErrors("password")
+	if len(fieldErrors) != 2 || fieldErrors[0].Field != "password" || fieldErrors[1].Code != "password_missing_character_class" {
+		t.Errorf("FieldErrors() = %+v", fieldErrors)
+	}
+}
+
+func TestQuickRatio(t *testing.T) {
+	// values from Python's difflib.SequenceMatcher(None, a, b).quick_ratio()
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"abcd", "bcde", 0.75},
+		{"alicesmith", "alice", 2.0 / 3.0},
+		{"password", "", 0},
+		{"", "", 1},
+	}
+
+	for _, tt := range tests {
+		if got := quickRatio(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("quickRatio(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// the ascii secret 12345678901234567890 used by the RFC 4226 and RFC 6238 test vectors
+const rfcTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestHOTPRFC4226(t *testing.T) {
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	for counter, code := range want {
+		if got := hotp([]byte("12345678901234567890"), int64(counter)); got != code {
+			t.Errorf("hotp(%d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+func TestValidateTOTPRFC6238(t *testing.T) {
+	// the RFC lists 8 digit SHA1 codes, the last 6 digits are the 6 digit codes
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			step, ok := ValidateTOTP(rfcTOTPSecret, tt.code, time.Unix(tt.unix, 0))
+			if !ok {
+				t.Fatalf("ValidateTOTP() rejected the RFC code at %d", tt.unix)
+			}
+			if want := tt.unix / totpPeriod; step != want {
+				t.Errorf("step = %d, want %d", step, want)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPDrift(t *testing.T) {
+	// 287082 is the code of step 1, 30 to 59 seconds after the epoch
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		unix   int64
+		want   bool
+	}{
+		{"previous step", rfcTOTPSecret, "287082", 60, true},
+		{"next step", rfcTOTPSecret, "287082", 29, true},
+		{"two steps late", rfcTOTPSecret, "287082", 90, false},
+		{"lowercase secret with spaces", " gezdgnbvgy3tqojqgezdgnbvgy3tqojq ", "287082", 59, true},
+		{"wrong code", rfcTOTPSecret, "287083", 59, false},
+		{"8 digit code", rfcTOTPSecret, "94287082", 59, false},
+		{"invalid secret", "not base32!", "287082", 59, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := ValidateTOTP(tt.secret, tt.code, time.Unix(tt.unix, 0)); ok != tt.want {
+				t.Errorf("ValidateTOTP() = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes() error = %v", err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("got %d codes, want 10", len(codes))
+	}
+
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("code %q is not in the xxxxx-xxxxx form", code)
+		}
+		// typed back from a printout
+		if HashRecoveryCode(code) != HashRecoveryCode(" "+strings.ToUpper(code)+" ") {
+			t.Errorf("hash of %q depends on case or surrounding spaces", code)
+		}
+	}
+}
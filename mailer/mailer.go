@@ -0,0 +1,131 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/google/uuid"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers messages, the driver is picked with MAILER_DRIVER
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// NewMailer returns the driver configured by MAILER_DRIVER, smtp, file or log,
+// there is no default so a deployment never drops its mails by accident
+func NewMailer() (Mailer, error) {
+	from := configs.AppConfig.GetOrDefault("MAILER_FROM", "no-reply@localhost")
+
+	switch driver := configs.AppConfig.Get("MAILER_DRIVER"); driver {
+	case "":
+		return nil, fmt.Errorf("MAILER_DRIVER is not configured")
+	case "smtp":
+		host := configs.AppConfig.Get("SMTP_HOST")
+		if host == "" {
+			return nil, fmt.Errorf("SMTP_HOST is not configured")
+		}
+		return &SMTPMailer{
+			From:     from,
+			Host:     host,
+			Port:     configs.AppConfig.GetOrDefault("SMTP_PORT", "587"),
+			Username: configs.AppConfig.Get("SMTP_USERNAME"),
+			Password: configs.AppConfig.Get("SMTP_PASSWORD"),
+		}, nil
+	case "file":
+		dir := configs.AppConfig.GetOrDefault("MAILER_FILE_DIR", "mails")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create mail directory failed: %w", err)
+		}
+		return &FileMailer{From: from, Dir: dir}, nil
+	case "log":
+		return &LogMailer{From: from}, nil
+	default:
+		return nil, fmt.Errorf("unknown mailer driver: %s", driver)
+	}
+}
+
+// renders the message in RFC 5322 form
+func (m Message) bytes(from string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(m.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", m.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(m.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// SMTPMailer sends through an SMTP server, using STARTTLS when the server offers it
+type SMTPMailer struct {
+	From     string
+	Host     string
+	Port     string
+	Username string
+	Password string
+}
+
+func (s *SMTPMailer) Send(ctx context.Context, message Message) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	// smtp.SendMail takes no context, run it so a slow server does not outlive the caller
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(s.Host, s.Port), auth, s.From, message.To, message.bytes(s.From))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("send mail failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// FileMailer writes every message as an .eml file, handy for local development and tests
+type FileMailer struct {
+	From string
+	Dir  string
+}
+
+func (f *FileMailer) Send(ctx context.Context, message Message) error {
+	name := filepath.Join(f.Dir, fmt.Sprintf("%s.eml", uuid.New().String()))
+	if err := os.WriteFile(name, message.bytes(f.From), 0o600); err != nil {
+		return fmt.Errorf("write mail failed: %w", err)
+	}
+	return nil
+}
+
+// LogMailer only logs that a message was sent, the body is left out since it carries
+// reset and verification tokens, use FileMailer to read the mails locally
+type LogMailer struct {
+	From string
+}
+
+func (l *LogMailer) Send(ctx context.Context, message Message) error {
+	log.Printf("mail from %s to %s: %s (body of %d bytes not logged)", l.From, strings.Join(message.To, ", "), message.Subject, len(message.Body))
+	return nil
+}
@@ -59,6 +59,9 @@ func echo_run(env string) {
 	// starting the app
 	app := echo.New()
 
+	// client ips come from X-Forwarded-For only when set by a proxy on a private network (haproxy)
+	app.IPExtractor = echo.ExtractIPFromXFFHeader()
+
 	// enable cross origin requests
 	app.Use(middleware.CORS())
 
@@ -155,6 +158,15 @@ func startServer(app *echo.Echo) {
 	// initialize services
 	django_auth_service.InitServices(django_auth_client)
 
+	// a permission for every route name so authorization and the permission catalogue can not drift apart
+	if configs.AppConfig.GetOrDefault("PERMISSION_SYNC_ON_STARTUP", "true") == "true" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := syncRoutePermissions(ctx, app); err != nil {
+			panic(err)
+		}
+		cancel()
+	}
+
 	HTTP_PORT := configs.AppConfig.Get("HTTP_PORT")
 	if app_tls == "on" {
 		CERT_FILE := "./server.pem"
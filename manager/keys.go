@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/database"
+	django_auth_service "github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/spf13/cobra"
+)
+
+var (
+	keys_env     string
+	rotatekeycli = &cobra.Command{
+		Use:   "rotatekeys",
+		Short: "Rotate token signing keys",
+		Long:  "Promote the next signing key to active, retire the active key and create a new next key. Running instances pick the change up on their next key reload.",
+		Run: func(cmd *cobra.Command, args []string) {
+			switch keys_env {
+			case "":
+				rotate_keys("dev")
+			default:
+				rotate_keys(keys_env)
+			}
+		},
+	}
+)
+
+func rotate_keys(env string) {
+	//  loading env file first
+	configs.AppConfig.SetEnv(env)
+
+	django_auth_client, err := database.ReturnMongoClient("django_auth")
+	if err != nil {
+		fmt.Printf("unable to connect to database: %v\n", err)
+		return
+	}
+
+	if _, err := django_auth_service.NewSigningKeyService(django_auth_client); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := django_auth_service.HandlerSigningKeyService.EnsureIndexes(ctx); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	keys, err := django_auth_service.HandlerSigningKeyService.Rotate(ctx)
+	if err != nil {
+		fmt.Printf("rotating signing keys failed: %v\n", err)
+		return
+	}
+
+	for _, key := range keys {
+		fmt.Printf("%-8s %-6s %s\n", key.State, key.Algorithm, key.Kid)
+	}
+}
+
+func init() {
+	rotatekeycli.Flags().StringVar(&keys_env, "env", "help", "Which environment to run for example prod or dev")
+	goFrame.AddCommand(rotatekeycli)
+}
@@ -1,13 +1,96 @@
 package manager
 
 import (
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/bushubdegefu/m-playground/common"
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/django-auth/models"
+	django_auth_service "github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/bushubdegefu/m-playground/django-auth/utils"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
 
 var AppRouteNames map[string]string
 
+// paths that are reachable without an access token
+var publicPaths = map[string]bool{
+	"/api/v1/blue_auth/stats":                              true,
+	"/api/v1/django_auth/login":                            true,
+	"/api/v1/django_auth/login/mfa":                        true,
+	"/api/v1/django_auth/login/mfa/enroll":                 true,
+	"/api/v1/django_auth/login/mfa/confirm":                true,
+	"/api/v1/django_auth/refresh":                          true,
+	"/api/v1/django_auth/password/reset":                   true,
+	"/api/v1/django_auth/password/reset/confirm":           true,
+	"/api/v1/django_auth/register":                         true,
+	"/api/v1/django_auth/register/verify":                  true,
+	"/api/v1/django_auth/register/resend":                  true,
+	"/api/v1/django_auth/.well-known/openid-configuration": true,
+	"/api/v1/django_auth/oidc/jwks":                        true,
+	"/api/v1/django_auth/oidc/authorize":                   true,
+	"/api/v1/django_auth/oidc/token":                       true,
+	"/api/v1/django_auth/oidc/userinfo":                    true,
+	"/django_auth/docs/doc.json":                           true,
+	"/django_auth/docs/*":                                  true,
+	"/metrics":                                             true,
+}
+
+// routes that also accept the route permission granted on the single document named by the path parameter
+var objectPermissionParams = map[string]string{
+	"GET /api/v1/django_auth/group/:group_id":                 "group_id",
+	"PATCH /api/v1/django_auth/group/:group_id":               "group_id",
+	"POST /api/v1/django_auth/usergroup/:group_id/:user_id":   "group_id",
+	"DELETE /api/v1/django_auth/usergroup/:group_id/:user_id": "group_id",
+	"GET /api/v1/django_auth/grouppermission/:group_id":       "group_id",
+	"GET /api/v1/django_auth/user/:user_id":                   "user_id",
+	"GET /api/v1/django_auth/permission/:permission_id":       "permission_id",
+}
+
+// how a route group authenticates requests
+const (
+	AuthModeHeader = "header"
+	AuthModeCookie = "cookie"
+	AuthModeBoth   = "both"
+)
+
+// auth mode of each route group by path prefix, paths outside a group use header tokens
+var routeGroupAuthModes = map[string]string{}
+
+func routeGroupAuthMode(path string) string {
+	mode, longest := AuthModeHeader, 0
+	for prefix, groupMode := range routeGroupAuthModes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			mode, longest = groupMode, len(prefix)
+		}
+	}
+	return mode
+}
+
+// reports whether the session cookie instead of the x-app-token header authenticates the request,
+// when a group allows both a header token wins so scripts keep working next to browsers
+func usesSessionCookie(ctx echo.Context) bool {
+	switch routeGroupAuthMode(ctx.Path()) {
+	case AuthModeCookie:
+		return true
+	case AuthModeBoth:
+		// AddAppTokenIfMissing puts "login" in the header when none was sent
+		if token := ctx.Request().Header.Get("x-app-token"); token != "" && token != "login" {
+			return false
+		}
+		_, err := ctx.Cookie(django_auth_service.SessionCookieName())
+		return err == nil
+	default:
+		return false
+	}
+}
+
 func GetApplicationRoutes(app *echo.Echo) {
 	// Lock the Mutex to ensure safe access to AppRouteNames
 
@@ -18,15 +101,34 @@ func GetApplicationRoutes(app *echo.Echo) {
 			// Skip routes without a name
 			continue
 		}
-		AppRouteNames[route.Path] = routeName
+		// the same path carries different permissions per method
+		AppRouteNames[route.Method+" "+route.Path] = routeName
+	}
+}
+
+// RoutePermissions maps every route name the auth middleware checks as a permission to its app label,
+// the label is the route group under /api/v1/. Public and authenticated only routes need no permission.
+func RoutePermissions(app *echo.Echo) map[string]string {
+	permissions := make(map[string]string)
+	for _, route := range app.Routes() {
+		if publicPaths[route.Path] || django_auth_service.AuthenticatedRouteNames[route.Name] {
+			continue
+		}
+		appLabel, _, found := strings.Cut(strings.TrimPrefix(route.Path, "/api/v1/"), "/")
+		// unnamed routes carry the handler's function name instead
+		if !found || !strings.HasPrefix(route.Path, "/api/v1/") || !strings.HasPrefix(route.Name, appLabel+"_") {
+			continue
+		}
+		permissions[route.Name] = appLabel
 	}
+	return permissions
 }
 
 // SetRouteName header based on path
 func SetRouteNameHeader(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(contx echo.Context) error {
 
-		routeName, exists := AppRouteNames[contx.Path()]
+		routeName, exists := AppRouteNames[contx.Request().Method+" "+contx.Path()]
 
 		// If the route name doesn't exist in the map, set it to "not-set"
 		if !exists {
@@ -36,24 +138,147 @@ func SetRouteNameHeader(next echo.HandlerFunc) echo.HandlerFunc {
 		// If the x-app-token header is missing, set a default value
 		contx.Request().Header.Set("route-name", routeName)
 
+		// only the auth middleware may flag a request as impersonated
+		contx.Request().Header.Del(utils.ImpersonatorHeader)
+
 		// Continue processing the request
 		return next(contx)
 	}
 }
 
 func NextAuthValidator(key string, ctx echo.Context) (bool, error) {
-	// fmt.Println(ctx.Path())
-	if ctx.Path() == "/api/v1/blue_auth/login" || ctx.Path() == "/api/v1/blue_auth/stats" {
+	if publicPaths[ctx.Path()] {
+		return true, nil
+	}
+
+	// personal access tokens come through the same header as JWTs
+	if strings.HasPrefix(key, django_auth_service.PersonalAccessTokenPrefix) {
+		return validatePersonalAccessToken(key, ctx)
+	}
+
+	// decoding the access token
+	claim, err := utils.ParseJWTToken(key, utils.AccessTokenType)
+	if err != nil {
+		return false, err
+	}
+
+	// rejecting logged out or revoked tokens
+	revoked, err := django_auth_service.HandlerRevokedTokenService.IsRevoked(ctx.Request().Context(), claim.ID)
+	if err != nil {
+		return false, err
+	}
+	if revoked {
+		return false, errors.New("token has been revoked")
+	}
+	ctx.Set("user_claim", claim)
+
+	// flagging the request in the access log with the superuser behind it
+	if claim.IsImpersonated() {
+		ctx.Request().Header.Set(utils.ImpersonatorHeader, claim.Act.UserID)
+	}
+
+	return authorizeRoute(ctx, claim)
+}
+
+// SessionAuthValidator authenticates browser requests with the session cookie
+func SessionAuthValidator(key string, ctx echo.Context) (bool, error) {
+	session, err := django_auth_service.HandlerSessionService.Authenticate(ctx.Request().Context(), key)
+	if err != nil {
+		return false, err
+	}
+
+	claim, err := django_auth_service.HandlerSessionService.UserClaim(ctx.Request().Context(), session)
+	if err != nil {
+		return false, err
+	}
+	ctx.Set("session", session)
+	ctx.Set("user_claim", claim)
+
+	return authorizeRoute(ctx, claim)
+}
+
+// checks the permission the route requires against the authenticated caller
+func authorizeRoute(ctx echo.Context, claim *utils.UserClaim) (bool, error) {
+	routeName := ctx.Request().Header.Get("route-name")
+	if django_auth_service.AuthenticatedRouteNames[routeName] {
 		return true, nil
 	}
-	fmt.Println("Key: ", key)
-	fmt.Println("Route Name: ", ctx.Request().Header.Get("route-name"))
 
-	//  You have to fix the NextAuthValidator function, it will let all values pass
-	// using required role access logic
+	// resolving the permissions from the user and all of the user's groups
+	permissions, err := django_auth_service.HandlerUserService.GetEffectivePermissions(ctx.Request().Context(), claim.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	// an impersonation never carries the superuser bypass, even when the user became a superuser after it started
+	if claim.IsImpersonated() {
+		permissions = slices.DeleteFunc(permissions, func(permission string) bool { return permission == "superuser" })
+	}
+
+	// route names are the permission required to access the route
+	if utils.CheckValueExistsInSlice(permissions, routeName) {
+		return true, nil
+	}
+
+	// falling back to a grant on the document the route acts on
+	if param, ok := objectPermissionParams[ctx.Request().Method+" "+ctx.Path()]; ok {
+		hasGrant := django_auth_service.HandlerObjectPermissionService.HasObjectPermission
+		if claim.IsImpersonated() {
+			hasGrant = django_auth_service.HandlerObjectPermissionService.HasObjectGrant
+		}
+		allowed, err := hasGrant(ctx.Request().Context(), claim.UserID, routeName, ctx.Param(param))
+		if err != nil {
+			return false, err
+		}
+		if allowed {
+			return true, nil
+		}
+	}
+
+	return false, echo.NewHTTPError(http.StatusForbidden, "you do not have permission to perform this action")
+}
+
+// personal access tokens only reach routes in their scopes that the owner can still access
+func validatePersonalAccessToken(key string, ctx echo.Context) (bool, error) {
+	token, err := django_auth_service.HandlerPersonalAccessTokenService.Authenticate(ctx.Request().Context(), key)
+	if err != nil {
+		return false, err
+	}
+
+	claim := &utils.UserClaim{UserID: token.UserID.Hex(), TokenType: utils.PersonalAccessTokenType}
+	claim.ID = token.ID.Hex()
+	ctx.Set("user_claim", claim)
+
+	permissions, err := django_auth_service.HandlerUserService.GetEffectivePermissions(ctx.Request().Context(), claim.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	// managing tokens, sessions or MFA needs the owner's own login, never a personal access token
+	routeName := ctx.Request().Header.Get("route-name")
+	if django_auth_service.AuthenticatedRouteNames[routeName] || !slices.Contains(token.Scopes, routeName) || !utils.CheckValueExistsInSlice(permissions, routeName) {
+		return false, echo.NewHTTPError(http.StatusForbidden, "token scopes do not allow this action")
+	}
+
 	return true, nil
 }
 
+// AuthErrorHandler responds with 403 for permission failures and 401 for everything else
+func AuthErrorHandler(err error, ctx echo.Context) error {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return ctx.JSON(httpErr.Code, common.ResponseHTTP{
+			Success: false,
+			Message: fmt.Sprintf("%v", httpErr.Message),
+		})
+	}
+
+	return ctx.JSON(http.StatusUnauthorized, common.ResponseHTTP{
+		Success: false,
+		Message: "Unauthorized",
+	})
+}
+
 // AddAppTokenIfMissing is a middleware that checks if the x-app-token header is present in the request. so that the login route can work
 func AddAppTokenIfMissing(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(contx echo.Context) error {
@@ -70,13 +295,53 @@ func AddAppTokenIfMissing(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
+// CSRFProtect requires the csrf token of the session in X-CSRF-Token on unsafe requests
+// authenticated by the session cookie, other sites can not send header tokens so those are left alone
+func CSRFProtect(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(contx echo.Context) error {
+		session, ok := contx.Get("session").(*models.Session)
+		if !ok {
+			return next(contx)
+		}
+
+		switch contx.Request().Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			return next(contx)
+		}
+
+		token := contx.Request().Header.Get(django_auth_service.CSRFHeaderName)
+		if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(session.CSRFToken)) != 1 {
+			return contx.JSON(http.StatusForbidden, common.ResponseHTTP{
+				Success: false,
+				Message: "missing or invalid csrf token",
+			})
+		}
+
+		return next(contx)
+	}
+}
+
 func MountGlobalMiddleware(app *echo.Echo) {
+	// route groups take header tokens, the session cookie or both, set with <GROUP>_AUTH_MODE
+	routeGroupAuthModes["/api/v1/django_auth"] = configs.AppConfig.GetOrDefault("DJANGO_AUTH_AUTH_MODE", AuthModeBoth)
+
 	// Mount the middleware
 	app.Use(SetRouteNameHeader)
 	app.Use(AddAppTokenIfMissing)
 	app.Use(middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
-		KeyLookup: "header:x-app-token",
-		Validator: NextAuthValidator,
+		Skipper:      usesSessionCookie,
+		KeyLookup:    "header:x-app-token",
+		Validator:    NextAuthValidator,
+		ErrorHandler: AuthErrorHandler,
+	}))
+	app.Use(middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+		Skipper: func(ctx echo.Context) bool {
+			return !usesSessionCookie(ctx) || publicPaths[ctx.Path()]
+		},
+		KeyLookup:    "cookie:" + django_auth_service.SessionCookieName(),
+		Validator:    SessionAuthValidator,
+		ErrorHandler: AuthErrorHandler,
 	}))
+	app.Use(CSRFProtect)
 
 }
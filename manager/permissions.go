@@ -0,0 +1,86 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bushubdegefu/m-playground/cache"
+	"github.com/bushubdegefu/m-playground/configs"
+	"github.com/bushubdegefu/m-playground/database"
+	django_auth "github.com/bushubdegefu/m-playground/django-auth"
+	django_auth_service "github.com/bushubdegefu/m-playground/django-auth/services"
+	"github.com/labstack/echo/v4"
+	"github.com/spf13/cobra"
+)
+
+var (
+	permissions_env    string
+	syncpermissionscli = &cobra.Command{
+		Use:   "syncpermissions",
+		Short: "Create a permission for every route name",
+		Long:  "Create the missing permissions for the route names checked by the auth middleware, the server does the same on startup unless PERMISSION_SYNC_ON_STARTUP is false.",
+		Run: func(cmd *cobra.Command, args []string) {
+			switch permissions_env {
+			case "":
+				sync_permissions("dev")
+			default:
+				sync_permissions(permissions_env)
+			}
+		},
+	}
+)
+
+func sync_permissions(env string) {
+	//  loading env file first
+	configs.AppConfig.SetEnv(env)
+
+	django_auth_client, err := database.ReturnMongoClient("django_auth")
+	if err != nil {
+		fmt.Printf("unable to connect to database: %v\n", err)
+		return
+	}
+
+	django_auth_service.AppCacheService, err = cache.NewCacheService()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	django_auth_service.NewPermissionService(django_auth_client)
+	django_auth_service.NewAuditLogService(django_auth_client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := django_auth_service.HandlerPermissionService.EnsureIndexes(ctx); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := django_auth_service.HandlerPermissionService.MigrateCodenames(ctx); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// the routes are only registered to read their names, nothing is served
+	app := echo.New()
+	django_auth.SetupRoutes(app)
+
+	if err := syncRoutePermissions(ctx, app); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// creates the permissions missing for the route names of the app
+func syncRoutePermissions(ctx context.Context, app *echo.Echo) error {
+	created, err := django_auth_service.HandlerPermissionService.SyncRoutePermissions(ctx, RoutePermissions(app))
+	if err != nil {
+		return fmt.Errorf("syncing route permissions failed: %w", err)
+	}
+	fmt.Printf("route permissions synced, %d created\n", created)
+	return nil
+}
+
+func init() {
+	syncpermissionscli.Flags().StringVar(&permissions_env, "env", "help", "Which environment to run for example prod or dev")
+	goFrame.AddCommand(syncpermissionscli)
+}
@@ -42,6 +42,43 @@ var (
 		},
 		[]string{"method", "path", "status_code", "service"},
 	)
+
+	// Failed login attempts by reason (invalid_credentials, invalid_mfa_code, locked)
+	LoginFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_login_failures_total",
+			Help: "Total number of failed login attempts by reason.",
+		},
+		[]string{"reason", "service"},
+	)
+
+	// Lockouts started, scope is username or ip
+	LoginLockouts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_login_lockouts_total",
+			Help: "Total number of temporary login lockouts by scope.",
+		},
+		[]string{"scope", "service"},
+	)
+
+	// Duration of authorization check requests, cache is hit when the permission set was cached
+	AuthzCheckDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "authz_check_duration_seconds",
+			Help:    "Histogram of authorization check duration (seconds) by permission cache hit or miss.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"cache", "service"},
+	)
+
+	// Authorization decisions by result (allowed, denied) and reason
+	AuthzDecisions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "authz_decisions_total",
+			Help: "Total number of authorization check decisions by result and reason.",
+		},
+		[]string{"result", "reason", "service"},
+	)
 )
 
 func InitProm(prom *prometheus.Registry) {
@@ -49,6 +86,10 @@ func InitProm(prom *prometheus.Registry) {
 	prom.MustRegister(cpuUsage)
 	prom.MustRegister(memUsage)
 	prom.MustRegister(httpDuration)
+	prom.MustRegister(LoginFailures)
+	prom.MustRegister(LoginLockouts)
+	prom.MustRegister(AuthzCheckDuration)
+	prom.MustRegister(AuthzDecisions)
 
 	// Start collecting system metrics in a goroutine
 	go collectSystemMetrics()